@@ -0,0 +1,560 @@
+package goconf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// CheckError is a single problem found by CheckFile
+type CheckError struct {
+	// File is the file the problem was found in
+	File string `json:"file"`
+	// Line is the 1-based line of the problem, 0 if unknown
+	Line int `json:"line,omitempty"`
+	// Column is the 1-based column of the problem, 0 if unknown
+	Column int `json:"column,omitempty"`
+	// Field is the dotted YAML path of the field, empty for file level problems
+	Field string `json:"field,omitempty"`
+	// Message describes the problem
+	Message string `json:"message"`
+}
+
+// Error formats the problem as file:line:column: message
+func (e CheckError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+	case e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	default:
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+}
+
+// CheckErrors holds all problems found by CheckFile, ordered by file and position
+type CheckErrors []CheckError
+
+// Error formats one problem per line
+func (e CheckErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// yamlLineRegex extracts the line number of yaml.v3 error messages
+var yamlLineRegex = regexp.MustCompile(`line (\d+): (.*)`)
+
+// CheckFile loads a YAML file into config, a pointer to a struct, and validates it
+// without running the service. It reports every problem it finds as CheckErrors
+// with file and line positions:
+//
+//   - YAML syntax errors and values that do not match the field types
+//   - keys that do not match any field, including keys of nested structs, and keys
+//     matching the same field with YAMLKeyMatchingLoose
+//   - invalid values in the env files
+//   - validation failures of StructValidator, with localized messages, use
+//     Loader.CheckFile to validate with another Validator or locale
+//
+// Values of the optional env files, in KEY=value format, override the YAML values
+// of fields with a matching env tag, later files taking precedence. The process
+// environment is not used, so the result does not depend on the machine running
+// the check. The `default` tags are applied before the file is read, like Load
+// does, and `replacedBy` fields are copied like in ParseYaml. CheckFile returns nil if no problem was found.
+//
+// Usage Example:
+//
+//	var cfg Config
+//	if err := goconf.CheckFile(&cfg, "config.prod.yaml", "prod.env"); err != nil {
+//	    var problems goconf.CheckErrors
+//	    if errors.As(err, &problems) {
+//	        for _, p := range problems {
+//	            fmt.Println(p) // config.prod.yaml:12:3: unknown key "prot"
+//	        }
+//	    }
+//	}
+func CheckFile(config interface{}, filePath string, envFiles ...string) error {
+	return defaultLoader.CheckFile(config, filePath, envFiles...)
+}
+
+// checkFile implements CheckFile for values, a pointer to a struct, validating
+// the struct with the Validator of the Loader in its locale
+func checkFile(l *Loader, values reflect.Value, filePath string, envFiles []string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read YAML file %s: %w", filePath, err)
+	}
+
+	config := values.Interface()
+	c := &checker{
+		file:      filePath,
+		root:      values.Elem().Type(),
+		loader:    l,
+		ambiguous: make(map[*yaml.Node]bool),
+		positions: make(map[string]*yaml.Node),
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		c.addYAMLError(err)
+		return c.errors()
+	}
+
+	// Defaults are applied before the file is read, like Load does before Register
+	if err := ApplyDefaults(config); err != nil {
+		c.add(nil, "", err.Error())
+	}
+
+	var root *yaml.Node
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		root = doc.Content[0]
+		if currentYAMLKeyMatching == YAMLKeyMatchingLoose {
+			for _, err := range matchYAMLKeys(root, c.root) {
+				c.add(err.key, "", err.message)
+				c.ambiguous[err.key] = true
+			}
+		}
+
+		c.checkKeys(root, c.root, "", "")
+		resetPresentMaps(values, yamlSource{node: root})
+
+		var typeErr *yaml.TypeError
+		if err := root.Decode(config); errors.As(err, &typeErr) {
+			for _, msg := range typeErr.Errors {
+				c.addYAMLError(errors.New(msg))
+			}
+		} else if err != nil {
+			c.addYAMLError(err)
+		}
+	}
+
+	if err := applyReplacements(config, yamlSource{node: root}); err != nil {
+		c.add(nil, "", err.Error())
+	}
+
+	for _, envFile := range envFiles {
+		if err := c.applyEnvFile(values.Elem(), envFile); err != nil {
+			return err
+		}
+	}
+
+	c.validate(config)
+
+	return c.errors()
+}
+
+// checker collects the problems of a single CheckFile call
+type checker struct {
+	file     string
+	problems CheckErrors
+	// root is the type of the configuration struct
+	root reflect.Type
+	// loader validates the configuration struct
+	loader *Loader
+	// ambiguous holds the keys matching more than one field, or a field matched
+	// by another key, with YAMLKeyMatchingLoose
+	ambiguous map[*yaml.Node]bool
+	// positions holds the key nodes of the fields found in the file, keyed by Go
+	// field path in the format of validator namespaces, e.g. Hosts[0]
+	positions map[string]*yaml.Node
+}
+
+func (c *checker) add(node *yaml.Node, field, message string) {
+	problem := CheckError{File: c.file, Field: field, Message: message}
+	if node != nil {
+		problem.Line = node.Line
+		problem.Column = node.Column
+	}
+
+	c.problems = append(c.problems, problem)
+}
+
+// addYAMLError adds a yaml.v3 error, taking the line from its message
+func (c *checker) addYAMLError(err error) {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+
+	problem := CheckError{File: c.file, Message: msg}
+	if m := yamlLineRegex.FindStringSubmatch(msg); m != nil {
+		problem.Line, _ = strconv.Atoi(m[1])
+		problem.Message = m[2]
+	}
+
+	c.problems = append(c.problems, problem)
+}
+
+// errors returns the problems ordered by file and position, problems without a
+// position last, or nil if there are none
+func (c *checker) errors() error {
+	if len(c.problems) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		a, b := c.problems[i], c.problems[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return b.Line == 0 || (a.Line != 0 && a.Line < b.Line)
+		}
+
+		return a.Column < b.Column
+	})
+
+	return c.problems
+}
+
+// checkKeys reports the keys of a mapping that match no field of the struct type t
+// and records the position of every field found
+func (c *checker) checkKeys(node *yaml.Node, t reflect.Type, goPath, yamlPath string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case node == nil || node.Kind == yaml.AliasNode:
+		return
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if node.Kind == yaml.SequenceNode {
+			for i, item := range node.Content {
+				itemPath := fmt.Sprintf("%s[%d]", goPath, i)
+				c.positions[itemPath] = item
+				c.checkKeys(item, t.Elem(), itemPath, fmt.Sprintf("%s[%d]", yamlPath, i))
+			}
+		}
+
+		return
+	case t.Kind() == reflect.Map:
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i].Value
+				itemPath := fmt.Sprintf("%s[%s]", goPath, key)
+				c.positions[itemPath] = node.Content[i]
+				c.checkKeys(node.Content[i+1], t.Elem(), itemPath, fmt.Sprintf("%s[%s]", yamlPath, key))
+			}
+		}
+
+		return
+	case t.Kind() != reflect.Struct || node.Kind != yaml.MappingNode || isYAMLLeaf(t):
+		return
+	}
+
+	fields := yamlFields(t)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+		if keyNode.Tag == "!!merge" {
+			continue
+		}
+
+		field, ok := fields[keyNode.Value]
+		if !ok {
+			if !c.ambiguous[keyNode] {
+				c.add(keyNode, joinYAMLPath(yamlPath, keyNode.Value), fmt.Sprintf("unknown key %q", keyNode.Value))
+			}
+
+			continue
+		}
+
+		fieldGoPath := joinGoPath(goPath, field.path)
+		fieldYAMLPath := joinYAMLPath(yamlPath, keyNode.Value)
+
+		c.positions[fieldGoPath] = keyNode
+		c.checkKeys(valueNode, field.typ, fieldGoPath, fieldYAMLPath)
+	}
+}
+
+// yamlField is a struct field reachable by a YAML key
+type yamlField struct {
+	// path is the Go field path relative to the struct, more than one
+	// segment for fields of inlined structs
+	path string
+	typ  reflect.Type
+}
+
+// yamlFields returns the fields of a struct keyed by their YAML key, including
+// the fields of inlined structs
+func yamlFields(t reflect.Type) map[string]yamlField {
+	fields := make(map[string]yamlField)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, flags, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if strings.Contains(flags, "inline") {
+			inlineType := sf.Type
+			for inlineType.Kind() == reflect.Ptr {
+				inlineType = inlineType.Elem()
+			}
+
+			if inlineType.Kind() == reflect.Struct {
+				for key, field := range yamlFields(inlineType) {
+					fields[key] = yamlField{path: sf.Name + "." + field.path, typ: field.typ}
+				}
+			}
+
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+
+		fields[name] = yamlField{path: sf.Name, typ: sf.Type}
+	}
+
+	return fields
+}
+
+var yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+// isYAMLLeaf reports whether a struct type decodes itself instead of field by field
+func isYAMLLeaf(t reflect.Type) bool {
+	ptr := reflect.PointerTo(t)
+
+	return ptr.Implements(yamlUnmarshalerType) || ptr.Implements(textUnmarshalerType)
+}
+
+func joinGoPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
+func joinYAMLPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+
+	return parent + "." + key
+}
+
+// envFileValue is a variable read from an env file
+type envFileValue struct {
+	value string
+	line  int
+}
+
+// applyEnvFile overrides the fields read from the environment by the values of the env file
+func (c *checker) applyEnvFile(values reflect.Value, envFile string) error {
+	vars, err := readEnvFile(envFile)
+	if err != nil {
+		return err
+	}
+
+	c.applyEnvValues(values, vars, envFile, RootEnvPath(), "")
+
+	return nil
+}
+
+func (c *checker) applyEnvValues(values reflect.Value, vars map[string]envFileValue, envFile string, env EnvPath, goPath string) {
+	for i := 0; i < values.NumField(); i++ {
+		field := values.Field(i)
+		structField := values.Type().Field(i)
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		fieldGoPath := joinGoPath(goPath, structField.Name)
+
+		if field.Kind() == reflect.Struct && !isYAMLLeaf(field.Type()) {
+			c.applyEnvValues(field, vars, envFile, env.Nested(structField), fieldGoPath)
+			continue
+		}
+
+		key, ok := env.Key(structField)
+		if !ok {
+			continue
+		}
+
+		v, ok := vars[key]
+		if !ok {
+			continue
+		}
+
+		if err := setFromString(field, v.value); err != nil {
+			c.problems = append(c.problems, CheckError{
+				File:    envFile,
+				Line:    v.line,
+				Field:   yamlPathOf(c.root, fieldGoPath),
+				Message: fmt.Sprintf("invalid value for %s: %v", key, err),
+			})
+		}
+	}
+}
+
+// readEnvFile reads KEY=value lines. Blank lines, comments and an export
+// prefix are ignored, and single or double quoted values are unquoted.
+func readEnvFile(path string) (map[string]envFileValue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	vars := make(map[string]envFileValue)
+	scanner := bufio.NewScanner(f)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(text, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=value", path, line)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid quoted value: %w", path, line, err)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			if before, _, found := strings.Cut(value, " #"); found {
+				value = strings.TrimSpace(before)
+			}
+		}
+
+		vars[strings.TrimSpace(key)] = envFileValue{value: value, line: line}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file %s: %w", path, err)
+	}
+
+	return vars, nil
+}
+
+// validate adds the validation failures of StructValidator at the position of
+// the field, or of its closest parent found in the file
+func (c *checker) validate(config interface{}) {
+	err := c.validateStruct(config)
+	if err == nil {
+		return
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		c.add(nil, "", err.Error())
+		return
+	}
+
+	messages := c.loader.Translate(err)
+
+	for _, fe := range validationErrors {
+		// Drop the root struct name, e.g. Config.Database.Host becomes Database.Host
+		_, goPath, _ := strings.Cut(fe.StructNamespace(), ".")
+
+		c.add(c.closestPosition(goPath), yamlPathOf(c.root, goPath), messages[fe.Namespace()])
+	}
+}
+
+// validateStruct validates the configuration struct, turning the panic of the
+// validator on a tag without a registered rule into an error
+func (c *checker) validateStruct(config interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to validate: %v", r)
+		}
+	}()
+
+	return c.loader.validator.Struct(config)
+}
+
+// closestPosition returns the key node of the field, or of its closest parent
+// found in the file
+func (c *checker) closestPosition(goPath string) *yaml.Node {
+	for path := goPath; path != ""; {
+		if node, ok := c.positions[path]; ok {
+			return node
+		}
+
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+
+	return nil
+}
+
+// yamlPathOf converts a Go field path such as Database.Hosts[0] into the YAML
+// path of the field, e.g. database.hosts[0]
+func yamlPathOf(t reflect.Type, goPath string) string {
+	var keys []string
+
+	for _, segment := range strings.Split(goPath, ".") {
+		name, index, _ := strings.Cut(segment, "[")
+
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			return goPath
+		}
+
+		sf, ok := t.FieldByName(name)
+		if !ok {
+			return goPath
+		}
+
+		key, flags, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if key == "" {
+			key = strings.ToLower(sf.Name)
+		}
+
+		t = sf.Type
+
+		if strings.Contains(flags, "inline") {
+			continue
+		}
+
+		if index != "" {
+			key += "[" + index
+			for i := strings.Count(index, "["); i >= 0; i-- {
+				for t.Kind() == reflect.Ptr {
+					t = t.Elem()
+				}
+				if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+					t = t.Elem()
+				}
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	return strings.Join(keys, ".")
+}
@@ -0,0 +1,187 @@
+package goconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type checkConfig struct {
+	Name     string   `yaml:"name" env:"NAME" validate:"required"`
+	Port     int      `yaml:"port" env:"PORT" validate:"gte=1024"`
+	Level    string   `yaml:"level" default:"info" validate:"oneof=debug info"`
+	Hosts    []string `yaml:"hosts" validate:"dive,min=3"`
+	Database struct {
+		Host string `yaml:"host" env:"HOST" validate:"required"`
+		Pool struct {
+			Size int `yaml:"size" validate:"gte=1"`
+		} `yaml:"pool"`
+	} `yaml:"database" envPrefix:"DB_"`
+	Common struct {
+		Region string `yaml:"region" validate:"len=2"`
+	} `yaml:",inline"`
+	Limits map[string]struct {
+		Max int `yaml:"max"`
+	} `yaml:"limits"`
+}
+
+func writeCheckFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestCheckFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		env      string
+		expected func(yamlFile, envFile string) CheckErrors
+	}{
+		{
+			name: "valid file",
+			yaml: "name: app\nport: 8080\nhosts: [example.com]\ndatabase:\n  host: db\n  pool:\n    size: 1\nregion: eu\n",
+		},
+		{
+			name: "unknown keys and validation failures",
+			yaml: "name: app\nprot: 8080\nport: 80\nhosts:\n  - example.com\n  - ab\ndatabase:\n  pool:\n    size: 0\n    max: 3\nregion: europe\nlimits:\n  api:\n    min: 1\n",
+			expected: func(yamlFile, _ string) CheckErrors {
+				return CheckErrors{
+					{File: yamlFile, Line: 2, Column: 1, Field: "prot", Message: `unknown key "prot"`},
+					{File: yamlFile, Line: 3, Column: 1, Field: "port", Message: "Port must be 1,024 or greater"},
+					{File: yamlFile, Line: 6, Column: 5, Field: "hosts[1]", Message: "Hosts[1] must be at least 3 characters in length"},
+					{File: yamlFile, Line: 7, Column: 1, Field: "database.host", Message: "Host is a required field"},
+					{File: yamlFile, Line: 9, Column: 5, Field: "database.pool.size", Message: "Size must be 1 or greater"},
+					{File: yamlFile, Line: 10, Column: 5, Field: "database.pool.max", Message: `unknown key "max"`},
+					{File: yamlFile, Line: 11, Column: 1, Field: "region", Message: "Region must be 2 characters in length"},
+					{File: yamlFile, Line: 14, Column: 5, Field: "limits[api].min", Message: `unknown key "min"`},
+				}
+			},
+		},
+		{
+			name: "type errors",
+			yaml: "name: app\nport: eighty\n",
+			expected: func(yamlFile, _ string) CheckErrors {
+				return CheckErrors{
+					{File: yamlFile, Line: 2, Message: "cannot unmarshal !!str `eighty` into int"},
+					{File: yamlFile, Line: 2, Column: 1, Field: "port", Message: "Port must be 1,024 or greater"},
+					{File: yamlFile, Field: "database.host", Message: "Host is a required field"},
+					{File: yamlFile, Field: "database.pool.size", Message: "Size must be 1 or greater"},
+					{File: yamlFile, Field: "region", Message: "Region must be 2 characters in length"},
+				}
+			},
+		},
+		{
+			name: "syntax error",
+			yaml: "name: app\n  port: 8080\n",
+			expected: func(yamlFile, _ string) CheckErrors {
+				return CheckErrors{{File: yamlFile, Line: 2, Message: "mapping values are not allowed in this context"}}
+			},
+		},
+		{
+			name: "env file overrides",
+			yaml: "name: app\nport: 80\ndatabase:\n  pool:\n    size: 1\nregion: eu\n",
+			env:  "# overrides\nexport PORT=8080\nDB_HOST='db'\nNAME=\"\"\n",
+			expected: func(yamlFile, _ string) CheckErrors {
+				return CheckErrors{{File: yamlFile, Line: 1, Column: 1, Field: "name", Message: "Name is a required field"}}
+			},
+		},
+		{
+			name: "invalid env file value",
+			yaml: "name: app\nport: 8080\ndatabase:\n  host: db\n  pool:\n    size: 1\nregion: eu\n",
+			env:  "PORT=high\n",
+			expected: func(_, envFile string) CheckErrors {
+				return CheckErrors{{
+					File:    envFile,
+					Line:    1,
+					Field:   "port",
+					Message: `invalid value for PORT: strconv.ParseInt: parsing "high": invalid syntax`,
+				}}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			yamlFile := writeCheckFile(t, "config.yaml", test.yaml)
+
+			var envFiles []string
+			if test.env != "" {
+				envFiles = append(envFiles, writeCheckFile(t, ".env", test.env))
+			}
+
+			var cfg checkConfig
+			err := CheckFile(&cfg, yamlFile, envFiles...)
+
+			if test.expected == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, "info", cfg.Level)
+				return
+			}
+
+			envFile := ""
+			if len(envFiles) > 0 {
+				envFile = envFiles[0]
+			}
+
+			assert.Equal(t, test.expected(yamlFile, envFile), err)
+		})
+	}
+}
+
+func TestCheckFileLooseKeys(t *testing.T) {
+	defer SetYAMLKeyMatching(YAMLKeyMatchingExact)
+	SetYAMLKeyMatching(YAMLKeyMatchingLoose)
+
+	yamlFile := writeCheckFile(t, "config.yaml", "Name: app\nport: 8080\nPORT: 8081\nDatabase:\n  HOST: db\n  pool: {SIZE: 1}\nRegion: eu\n")
+
+	var cfg checkConfig
+	err := CheckFile(&cfg, yamlFile)
+	assert.Equal(t, CheckErrors{
+		{File: yamlFile, Line: 3, Column: 1, Message: `keys "port" and "PORT" both match the field Port`},
+	}, err)
+	assert.Equal(t, "db", cfg.Database.Host)
+	assert.Equal(t, 1, cfg.Database.Pool.Size)
+}
+
+func TestCheckFileErrors(t *testing.T) {
+	var cfg checkConfig
+
+	err := CheckFile(cfg, "config.yaml")
+	assert.EqualError(t, err, "config must be a pointer to a struct, got goconf.checkConfig")
+
+	err = CheckFile(&cfg, filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read YAML file")
+
+	yamlFile := writeCheckFile(t, "config.yaml", "name: app\n")
+	err = CheckFile(&cfg, yamlFile, writeCheckFile(t, ".env", "INVALID\n"))
+	assert.ErrorContains(t, err, ".env:1: expected KEY=value")
+}
+
+func TestCheckFileUnknownRule(t *testing.T) {
+	var cfg struct {
+		Name string `yaml:"name" validate:"unknown_rule"`
+	}
+
+	yamlFile := writeCheckFile(t, "config.yaml", "name: app\n")
+	err := CheckFile(&cfg, yamlFile)
+	assert.Equal(t, CheckErrors{
+		{File: yamlFile, Message: "failed to validate: Undefined validation function 'unknown_rule' on field 'Name'"},
+	}, err)
+}
+
+func TestCheckErrors(t *testing.T) {
+	err := CheckErrors{
+		{File: "config.yaml", Line: 2, Column: 3, Message: "first"},
+		{File: "config.yaml", Line: 4, Message: "second"},
+		{File: ".env", Message: "third"},
+	}
+
+	assert.Equal(t, "config.yaml:2:3: first\nconfig.yaml:4: second\n.env: third", err.Error())
+}
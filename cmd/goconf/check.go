@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/wgarunap/goconf"
+)
+
+// checkProgram is the program run by goconf check. It is built as a package of
+// the module of the config struct, so the struct can be imported even from an
+// internal package, and prints the problems found by goconf.CheckFile as JSON.
+// Importing the package runs its init functions.
+var checkProgram = template.Must(template.New("check").Parse(`// Code generated by goconf check. DO NOT EDIT.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/wgarunap/goconf"
+
+	config "{{.PkgPath}}"
+)
+
+func main() {
+	goconf.SetYAMLKeyMatching({{printf "%q" .KeyMatching}})
+	goconf.SetEnvNaming(goconf.EnvNaming{Derive: {{.EnvNaming.Derive}}, Prefix: {{printf "%q" .EnvNaming.Prefix}}})
+
+	var cfg config.{{.TypeName}}
+	err := goconf.CheckFile(&cfg, os.Args[1], os.Args[2:]...)
+
+	problems := goconf.CheckErrors{}
+	if err != nil && !errors.As(err, &problems) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(problems); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
+`))
+
+// stringsFlag is a flag that can be repeated
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runCheck validates a YAML file against a config struct
+//
+//	goconf check -type Config -f config.yaml [-env prod.env] [-format text|json|github]
+//	    [-loose-keys] [-derive-env [-env-prefix APP_]] [packages]
+//
+// The check builds and runs a program importing the package of the config struct,
+// so the init functions of the package and of its imports run.
+func runCheck(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	typeName := flags.String("type", "", "name of the config struct")
+	file := flags.String("f", "", "YAML file to check")
+	format := flags.String("format", "text", "output format, text, json or github for GitHub Actions annotations")
+	var envFiles stringsFlag
+	flags.Var(&envFiles, "env", "env file overriding the YAML values, can be repeated")
+	looseKeys := flags.Bool("loose-keys", false, "match YAML keys across naming conventions, like goconf.SetYAMLKeyMatching")
+	applyEnvNaming := envNamingFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := applyEnvNaming(); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return errors.New("the -f flag is required")
+	}
+
+	if _, ok := checkFormatters[*format]; !ok {
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+
+	options := checkOptions{KeyMatching: goconf.YAMLKeyMatchingExact, EnvNaming: goconf.CurrentEnvNaming()}
+	if *looseKeys {
+		options.KeyMatching = goconf.YAMLKeyMatchingLoose
+	}
+
+	problems, err := checkFile(flags.Args(), *typeName, *file, envFiles, options)
+	if err != nil {
+		return err
+	}
+
+	if err := checkFormatters[*format](stdout, problems); err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problems in %s", len(problems), *file)
+	}
+
+	return nil
+}
+
+// checkOptions holds the goconf settings of the check program
+type checkOptions struct {
+	KeyMatching goconf.YAMLKeyMatching
+	EnvNaming   goconf.EnvNaming
+}
+
+// checkFile runs goconf.CheckFile for the config struct in a generated program.
+// The program is written to a temporary directory and built into the module of
+// the struct with an overlay, so nothing is written to the module directory.
+func checkFile(patterns []string, typeName, file string, envFiles []string, options checkOptions) (goconf.CheckErrors, error) {
+	pkg, _, err := findType(patterns, typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	if pkg.Name == "main" || pkg.Module == nil {
+		return nil, fmt.Errorf("type %s must be declared in an importable package of a module", typeName)
+	}
+
+	// The program is built in the module directory, so file names are made
+	// absolute and mapped back to the given names in the problems
+	names := make(map[string]string)
+	var files []string
+	for _, name := range append([]string{file}, envFiles...) {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+
+		names[abs] = name
+		files = append(files, abs)
+	}
+
+	dir, err := os.MkdirTemp("", "goconf-check-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	binary, err := buildCheckProgram(dir, pkg.Module.Dir, struct {
+		checkOptions
+		PkgPath  string
+		TypeName string
+	}{options, pkg.PkgPath, typeName})
+	if err != nil {
+		return nil, err
+	}
+
+	var output, errOutput bytes.Buffer
+	cmd := exec.Command(binary, files...)
+	cmd.Stdout = &output
+	cmd.Stderr = &errOutput
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run check: %w\n%s", err, strings.TrimSpace(errOutput.String()))
+	}
+
+	var problems goconf.CheckErrors
+	if err := json.Unmarshal(output.Bytes(), &problems); err != nil {
+		return nil, fmt.Errorf("failed to read check result: %w", err)
+	}
+
+	for i := range problems {
+		if name, ok := names[problems[i].File]; ok {
+			problems[i].File = name
+		}
+	}
+
+	return problems, nil
+}
+
+// buildCheckProgram writes the check program to dir and builds it as the package
+// .goconf-check of the module in moduleDir, which only exists in the overlay
+// passed to go build. It returns the path of the executable built in dir.
+func buildCheckProgram(dir, moduleDir string, data interface{}) (string, error) {
+	var program bytes.Buffer
+	if err := checkProgram.Execute(&program, data); err != nil {
+		return "", err
+	}
+
+	source := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(source, program.Bytes(), 0o600); err != nil {
+		return "", err
+	}
+
+	overlay, err := json.Marshal(map[string]map[string]string{
+		"Replace": {filepath.Join(moduleDir, ".goconf-check", "main.go"): source},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	overlayFile := filepath.Join(dir, "overlay.json")
+	if err := os.WriteFile(overlayFile, overlay, 0o600); err != nil {
+		return "", err
+	}
+
+	binary := filepath.Join(dir, "check")
+	var errOutput bytes.Buffer
+	cmd := exec.Command("go", "build", "-overlay", overlayFile, "-o", binary, "./.goconf-check")
+	cmd.Dir = moduleDir
+	cmd.Stderr = &errOutput
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to build check: %w\n%s", err, strings.TrimSpace(errOutput.String()))
+	}
+
+	return binary, nil
+}
+
+// checkFormatters write the problems found by goconf check
+var checkFormatters = map[string]func(w io.Writer, problems goconf.CheckErrors) error{
+	"text":   writeCheckText,
+	"json":   writeCheckJSON,
+	"github": writeCheckGitHub,
+}
+
+func writeCheckText(w io.Writer, problems goconf.CheckErrors) error {
+	for _, p := range problems {
+		if _, err := fmt.Fprintln(w, p.Error()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeCheckJSON(w io.Writer, problems goconf.CheckErrors) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(problems)
+}
+
+// writeCheckGitHub writes GitHub Actions workflow commands, which annotate the
+// problems in the pull request diff
+func writeCheckGitHub(w io.Writer, problems goconf.CheckErrors) error {
+	data := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	property := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+	for _, p := range problems {
+		params := []string{"file=" + property.Replace(p.File)}
+		if p.Line > 0 {
+			params = append(params, fmt.Sprintf("line=%d", p.Line))
+		}
+		if p.Column > 0 {
+			params = append(params, fmt.Sprintf("col=%d", p.Column))
+		}
+		if p.Field != "" {
+			params = append(params, "title="+property.Replace(p.Field))
+		}
+
+		if _, err := fmt.Fprintf(w, "::error %s::%s\n", strings.Join(params, ","), data.Replace(p.Message)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,1287 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/types"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// decoderKind classifies the types supported by the generated decoders
+type decoderKind int
+
+const (
+	decodeString decoderKind = iota
+	decodeBool
+	decodeInt
+	decodeUint
+	decodeFloat
+	decodeDuration
+	decodeSlice
+	decodeStruct
+)
+
+// decoderType describes the Go type of a field decoded by the generated code
+type decoderType struct {
+	Kind decoderKind
+	// Bits is the size of numbers, 0 for int and uint
+	Bits int
+	// Expr is the Go type expression, empty for anonymous structs
+	Expr string
+	// Elem is the element type of slices
+	Elem *decoderType
+	// Struct is the underlying type of nested structs
+	Struct *types.Struct
+}
+
+// decoderField is a field of a struct decoded by the generated code
+type decoderField struct {
+	Name     string
+	Exported bool
+	// Path is the Go field path from the root struct
+	Path []string
+	// Expr accesses the field from the receiver, e.g. c.Database.Host
+	Expr string
+	// Zero accesses the field from the zero value of the root struct, e.g. Config{}.Database.Host
+	Zero string
+	Type decoderType
+	Tag  reflect.StructTag
+
+	// Env is the environment variable including the envPrefix of the parent structs
+	Env string
+	// EnvPrefix is the envPrefix of the parent structs
+	EnvPrefix     string
+	Required      bool
+	NotEmpty      bool
+	EnvDefault    string
+	HasEnvDefault bool
+	// Default holds the source independent `default` tag
+	Default    string
+	HasDefault bool
+
+	// YAMLKey is the key the field is decoded from, empty if excluded with `yaml:"-"`
+	YAMLKey string
+
+	Secret bool
+	Fields []decoderField
+}
+
+// decoderFile collects the imports and helper functions used by the generated
+// decoders of a file
+type decoderFile struct {
+	imports map[string]bool
+	helpers map[string]bool
+}
+
+func newDecoderFile() *decoderFile {
+	return &decoderFile{imports: make(map[string]bool), helpers: make(map[string]bool)}
+}
+
+// decoder generates the reflection-free decoding, validation and field listing
+// of a config with the decoder=generated option
+type decoder struct {
+	*decoderFile
+	pkg    *types.Package
+	config genConfig
+	fields []decoderField
+	// types holds the expressions of the field types listed by ListFields
+	types []string
+}
+
+// decoderMethods are the methods declared by the generated decoders
+var decoderMethods = []string{"decodeEnv", "decodeYAML", "valid", "ListFields"}
+
+// newDecoder describes the fields of a config, failing for fields the generated
+// code cannot decode exactly like ParseEnv and ParseYaml
+func newDecoder(pkg *types.Package, config genConfig, file *decoderFile) (*decoder, error) {
+	d := &decoder{decoderFile: file, pkg: pkg, config: config}
+	st := pkg.Scope().Lookup(config.TypeName).Type().Underlying().(*types.Struct)
+
+	fields, err := d.structFields(st, "c", config.TypeName+"{}", nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w, use decoder=reflect", err)
+	}
+	d.fields = fields
+
+	return d, nil
+}
+
+// structFields describes the fields of a nested struct reached through expr
+func (d *decoder) structFields(st *types.Struct, expr, zero string, path []string, envPrefix string) ([]decoderField, error) {
+	fields := make([]decoderField, 0, st.NumFields())
+	yamlKeys := make(map[string]string)
+
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		f := decoderField{
+			Name:      v.Name(),
+			Exported:  v.Exported(),
+			Path:      append(path[:len(path):len(path)], v.Name()),
+			Expr:      expr + "." + v.Name(),
+			Zero:      zero + "." + v.Name(),
+			Tag:       reflect.StructTag(st.Tag(i)),
+			EnvPrefix: envPrefix,
+		}
+		name := strings.Join(f.Path, ".")
+
+		if v.Embedded() {
+			return nil, fmt.Errorf("embedded field %s is not supported by the generated decoder", name)
+		}
+
+		t, err := d.typeOf(v.Type())
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		f.Type = t
+
+		if t.Kind == decodeStruct {
+			f.Fields, err = d.structFields(t.Struct, f.Expr, f.Zero, f.Path, envPrefix+f.Tag.Get("envPrefix"))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if f.Exported {
+			if err := d.parseTags(&f); err != nil {
+				return nil, fmt.Errorf("field %s: %w", name, err)
+			}
+
+			if other, ok := yamlKeys[f.YAMLKey]; ok && f.YAMLKey != "" && other != name {
+				return nil, fmt.Errorf("fields %s and %s share the YAML key %q", other, name, f.YAMLKey)
+			}
+			yamlKeys[f.YAMLKey] = name
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// parseTags reads the struct tags of an exported field
+func (d *decoder) parseTags(f *decoderField) error {
+	env := f.Tag.Get("env")
+	name, options, _ := strings.Cut(env, ",")
+	for _, option := range strings.Split(options, ",") {
+		switch option {
+		case "":
+		case "required":
+			f.Required = true
+		case "notEmpty":
+			f.NotEmpty = true
+		default:
+			return fmt.Errorf("env option %q is not supported by the generated decoder", option)
+		}
+	}
+
+	f.EnvDefault, f.HasEnvDefault = f.Tag.Lookup("envDefault")
+	f.Default, f.HasDefault = f.Tag.Lookup("default")
+
+	switch {
+	case name == "-" && (f.HasEnvDefault || f.Required || f.NotEmpty):
+		return errors.New("env options of ignored fields are not supported by the generated decoder")
+	case name == "" && (f.Required || f.NotEmpty):
+		return errors.New("env options without a variable are not supported by the generated decoder")
+	case name != "-" && name != "":
+		f.Env = f.EnvPrefix + name
+	}
+
+	if _, ok := f.Tag.Lookup("replacedBy"); ok {
+		return errors.New("the replacedBy tag is not supported by the generated decoder")
+	}
+
+	yamlName, yamlOptions, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if strings.Contains(yamlOptions, "inline") {
+		return errors.New("inline structs are not supported by the generated decoder")
+	}
+
+	switch yamlName {
+	case "-":
+	case "":
+		f.YAMLKey = strings.ToLower(f.Name)
+	default:
+		f.YAMLKey = yamlName
+	}
+
+	f.Secret = f.Tag.Get("secret") == "true"
+
+	if f.Type.Kind == decodeStruct {
+		if env != "" || f.HasEnvDefault || f.HasDefault || yamlName == "-" {
+			return errors.New("env, default and ignored yaml tags of nested structs are not supported by the generated decoder")
+		}
+
+		return nil
+	}
+
+	if f.HasEnvDefault {
+		if _, err := d.envLiteral(f.Type, f.EnvDefault, f.Tag.Get("envSeparator")); err != nil {
+			return fmt.Errorf("invalid envDefault %q: %w", f.EnvDefault, err)
+		}
+
+		if _, err := d.defaultLiteral(f.Type, f.EnvDefault); err != nil {
+			return fmt.Errorf("invalid envDefault %q: %w", f.EnvDefault, err)
+		}
+	}
+
+	if f.HasDefault {
+		if _, err := d.defaultLiteral(f.Type, f.Default); err != nil {
+			return fmt.Errorf("invalid default %q: %w", f.Default, err)
+		}
+	}
+
+	return nil
+}
+
+// typeOf classifies a field type, failing for types the generated code does not decode
+func (d *decoder) typeOf(t types.Type) (decoderType, error) {
+	expr := types.TypeString(t, d.qualifier)
+	unsupported := fmt.Errorf("type %s is not supported by the generated decoder", expr)
+
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration" {
+			return decoderType{Kind: decodeDuration, Bits: 64, Expr: expr}, nil
+		}
+
+		if obj.Pkg() != d.pkg {
+			return decoderType{}, unsupported
+		}
+
+		methods := types.NewMethodSet(types.NewPointer(t))
+		for _, name := range []string{"UnmarshalText", "UnmarshalYAML"} {
+			if methods.Lookup(d.pkg, name) != nil {
+				return decoderType{}, fmt.Errorf("type %s implements %s, which is not supported by the generated decoder", expr, name)
+			}
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch u.Kind() {
+		case types.String:
+			return decoderType{Kind: decodeString, Expr: expr}, nil
+		case types.Bool:
+			return decoderType{Kind: decodeBool, Expr: expr}, nil
+		case types.Int:
+			return decoderType{Kind: decodeInt, Expr: expr}, nil
+		case types.Int8, types.Int16, types.Int32, types.Int64:
+			return decoderType{Kind: decodeInt, Bits: basicBits(u.Kind()), Expr: expr}, nil
+		case types.Uint:
+			return decoderType{Kind: decodeUint, Expr: expr}, nil
+		case types.Uint8, types.Uint16, types.Uint32, types.Uint64:
+			return decoderType{Kind: decodeUint, Bits: basicBits(u.Kind()), Expr: expr}, nil
+		case types.Float32:
+			return decoderType{Kind: decodeFloat, Bits: 32, Expr: expr}, nil
+		case types.Float64:
+			return decoderType{Kind: decodeFloat, Bits: 64, Expr: expr}, nil
+		}
+	case *types.Slice:
+		elem, err := d.typeOf(u.Elem())
+		if err != nil || elem.Kind == decodeSlice || elem.Kind == decodeStruct {
+			return decoderType{}, unsupported
+		}
+
+		return decoderType{Kind: decodeSlice, Expr: expr, Elem: &elem}, nil
+	case *types.Struct:
+		if _, ok := t.(*types.Named); !ok {
+			expr = ""
+		}
+
+		return decoderType{Kind: decodeStruct, Expr: expr, Struct: u}, nil
+	}
+
+	return decoderType{}, unsupported
+}
+
+func (d *decoder) qualifier(pkg *types.Package) string {
+	if pkg == d.pkg {
+		return ""
+	}
+
+	return pkg.Name()
+}
+
+func basicBits(kind types.BasicKind) int {
+	switch kind {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// envBits returns the size the env parser parses a number with, 32 for int and uint
+func envBits(t decoderType) int {
+	if t.Bits == 0 {
+		return 32
+	}
+
+	return t.Bits
+}
+
+// envLiteral returns the Go literal of an envDefault parsed like the env parser
+// parses variables: numbers are decimal, int and uint have 32 bits and slice
+// elements are split by the separator without trimming
+func (d *decoder) envLiteral(t decoderType, raw, separator string) (string, error) {
+	if separator == "" {
+		separator = ","
+	}
+
+	return d.literal(t, raw, true, separator)
+}
+
+// defaultLiteral returns the Go literal of a default parsed like ApplyDefaults
+// parses it: numbers may have a base prefix and slice elements are trimmed
+func (d *decoder) defaultLiteral(t decoderType, raw string) (string, error) {
+	return d.literal(t, raw, false, ",")
+}
+
+func (d *decoder) literal(t decoderType, raw string, env bool, separator string) (string, error) {
+	if t.Kind == decodeSlice {
+		if raw == "" {
+			return "nil", nil
+		}
+
+		var items []string
+		for _, part := range strings.Split(raw, separator) {
+			if !env {
+				part = strings.TrimSpace(part)
+			}
+
+			item, err := d.literal(*t.Elem, part, env, separator)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, item)
+		}
+
+		return t.Expr + "{" + strings.Join(items, ", ") + "}", nil
+	}
+
+	base, bits := 0, t.Bits
+	if bits == 0 {
+		bits = strconv.IntSize
+	}
+
+	if env {
+		base, bits = 10, envBits(t)
+	}
+
+	switch t.Kind {
+	case decodeString:
+		return strconv.Quote(raw), nil
+	case decodeBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatBool(b), nil
+	case decodeInt:
+		i, err := strconv.ParseInt(raw, base, bits)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatInt(i, 10), nil
+	case decodeUint:
+		u, err := strconv.ParseUint(raw, base, bits)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatUint(u, 10), nil
+	case decodeFloat:
+		f, err := strconv.ParseFloat(raw, t.Bits)
+		if err != nil {
+			return "", err
+		}
+
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return "", fmt.Errorf("%s has no Go literal", raw)
+		}
+
+		return strconv.FormatFloat(f, 'g', -1, t.Bits), nil
+	case decodeDuration:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return "", err
+		}
+
+		d.imports["time"] = true
+
+		return durationLiteral(duration), nil
+	}
+
+	return "", fmt.Errorf("type %s has no default", t.Expr)
+}
+
+// mustLiteral returns the literal of an envDefault or default checked by parseTags
+func mustLiteral(literal string, err error) string {
+	if err != nil {
+		panic(err)
+	}
+
+	return literal
+}
+
+// durationLiteral returns a readable literal of a duration, e.g. 30 * time.Second
+func durationLiteral(duration time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+
+	for _, u := range units {
+		if duration != 0 && duration%u.unit == 0 {
+			return strconv.FormatInt(int64(duration/u.unit), 10) + " * " + u.name
+		}
+	}
+
+	return strconv.FormatInt(int64(duration), 10)
+}
+
+// zeroExpr returns an expression reporting whether the field holds its zero value
+// like reflect.Value.IsZero, which does not treat -0.0 as zero
+func (d *decoder) zeroExpr(f decoderField) string {
+	switch f.Type.Kind {
+	case decodeString:
+		return f.Expr + ` == ""`
+	case decodeBool:
+		return "!" + f.Expr
+	case decodeFloat:
+		d.imports["math"] = true
+		return "math.Float64bits(float64(" + f.Expr + ")) == 0"
+	case decodeSlice:
+		return f.Expr + " == nil"
+	case decodeStruct:
+		if len(f.Fields) == 0 {
+			return "true"
+		}
+
+		checks := make([]string, len(f.Fields))
+		for i, nested := range f.Fields {
+			checks[i] = d.zeroExpr(nested)
+		}
+
+		return strings.Join(checks, " && ")
+	default:
+		return f.Expr + " == 0"
+	}
+}
+
+// nonZeroExpr returns an expression reporting whether a field that is not a nested
+// struct holds a value other than its zero value
+func (d *decoder) nonZeroExpr(f decoderField) string {
+	switch f.Type.Kind {
+	case decodeString:
+		return f.Expr + ` != ""`
+	case decodeBool:
+		return f.Expr
+	case decodeFloat:
+		d.imports["math"] = true
+		return "math.Float64bits(float64(" + f.Expr + ")) != 0"
+	case decodeSlice:
+		return f.Expr + " != nil"
+	default:
+		return f.Expr + " != 0"
+	}
+}
+
+// leafFields returns the exported fields that are not nested structs, in order
+func leafFields(fields []decoderField) []decoderField {
+	var leaves []decoderField
+
+	for _, f := range fields {
+		switch {
+		case !f.Exported:
+		case f.Type.Kind == decodeStruct:
+			leaves = append(leaves, leafFields(f.Fields)...)
+		default:
+			leaves = append(leaves, f)
+		}
+	}
+
+	return leaves
+}
+
+// walkExported calls fn for the exported fields, including the fields of nested structs
+func walkExported(fields []decoderField, fn func(f decoderField)) {
+	for _, f := range fields {
+		if f.Exported {
+			fn(f)
+			walkExported(f.Fields, fn)
+		}
+	}
+}
+
+// generate returns the generated methods and reports whether valid was generated
+func (d *decoder) generate() (string, bool) {
+	var b bytes.Buffer
+
+	for _, source := range d.config.Sources {
+		if source == "env" {
+			d.generateEnv(&b)
+		} else {
+			d.generateYAML(&b)
+		}
+	}
+
+	hasValid := d.generateValid(&b)
+	d.generateListFields(&b)
+
+	return b.String(), hasValid
+}
+
+// generateEnv writes decodeEnv, which follows the env parser used by ParseEnv
+func (d *decoder) generateEnv(b *bytes.Buffer) {
+	d.imports["os"] = true
+
+	fmt.Fprintf(b, `
+// decodeEnv loads the environment into c without reflection. It returns false
+// if a value cannot be decoded, goconf.ParseEnv then reports the error.
+func (c *%s) decodeEnv() bool {
+`, d.config.TypeName)
+
+	// Fields without an env tag are read from derived names by goconf.ParseEnv
+	for _, f := range leafFields(d.fields) {
+		if f.Env == "" {
+			b.WriteString("\tif goconf.CurrentEnvNaming().Derive {\n\t\treturn false\n\t}\n\n")
+			break
+		}
+	}
+
+	// The env parser reads nested structs and fields without an env tag from
+	// the variable named like their envPrefix
+	seen := make(map[string]bool)
+	walkExported(d.fields, func(f decoderField) {
+		if f.Env == "" && f.EnvPrefix != "" && !seen[f.EnvPrefix] {
+			seen[f.EnvPrefix] = true
+			fmt.Fprintf(b, "\tif value, ok := os.LookupEnv(%q); ok && value != \"\" {\n\t\treturn false\n\t}\n\n", f.EnvPrefix)
+		}
+	})
+
+	for _, f := range leafFields(d.fields) {
+		d.envField(b, f)
+	}
+
+	b.WriteString("\treturn true\n}\n")
+}
+
+// envField writes the decoding of the variable of a field
+func (d *decoder) envField(b *bytes.Buffer, f decoderField) {
+	envDefault := ""
+	if f.HasEnvDefault {
+		envDefault = mustLiteral(d.envLiteral(f.Type, f.EnvDefault, f.Tag.Get("envSeparator")))
+	}
+
+	// Fields without a variable only receive their envDefault
+	if f.Env == "" {
+		if f.EnvDefault != "" {
+			fmt.Fprintf(b, "\t%s = %s\n\n", f.Expr, envDefault)
+		}
+
+		return
+	}
+
+	fmt.Fprintf(b, "\tif value, ok := os.LookupEnv(%q); ok && value != \"\" {\n", f.Env)
+	d.parseEnvValue(b, f.Type, f.Tag.Get("envSeparator"), "value", f.Expr, "\t\t")
+
+	switch {
+	case f.EnvDefault != "":
+		fmt.Fprintf(b, "\t} else {\n\t\t%s = %s\n\t}\n\n", f.Expr, envDefault)
+	case f.NotEmpty:
+		b.WriteString("\t} else {\n\t\treturn false\n\t}\n\n")
+	case f.Required && !f.HasEnvDefault:
+		b.WriteString("\t} else if !ok {\n\t\treturn false\n\t}\n\n")
+	default:
+		b.WriteString("\t}\n\n")
+	}
+}
+
+// parseEnvValue writes the parsing of the variable src into dst
+func (d *decoder) parseEnvValue(b *bytes.Buffer, t decoderType, separator, src, dst, indent string) {
+	if t.Kind != decodeSlice {
+		d.parseScalar(b, t, src, dst, indent, strconv.Itoa(envBits(t)))
+		return
+	}
+
+	if separator == "" {
+		separator = ","
+	}
+
+	d.imports["strings"] = true
+	fmt.Fprintf(b, "%sparts := strings.Split(%s, %q)\n", indent, src, separator)
+	fmt.Fprintf(b, "%sitems := make(%s, len(parts))\n", indent, t.Expr)
+	fmt.Fprintf(b, "%sfor i, part := range parts {\n", indent)
+	d.parseScalar(b, *t.Elem, "part", "items[i]", indent+"\t", strconv.Itoa(envBits(*t.Elem)))
+	fmt.Fprintf(b, "%s}\n", indent)
+	fmt.Fprintf(b, "%s%s = items\n", indent, dst)
+}
+
+// parseScalar writes the parsing of the string src into dst, numbers are
+// decimal with the given bit size
+func (d *decoder) parseScalar(b *bytes.Buffer, t decoderType, src, dst, indent, bits string) {
+	var parse, parsedType string
+
+	switch t.Kind {
+	case decodeString:
+		if t.Expr != "string" {
+			src = t.Expr + "(" + src + ")"
+		}
+
+		fmt.Fprintf(b, "%s%s = %s\n", indent, dst, src)
+
+		return
+	case decodeBool:
+		parse, parsedType = "strconv.ParseBool("+src+")", "bool"
+	case decodeInt:
+		parse, parsedType = "strconv.ParseInt("+src+", 10, "+bits+")", "int64"
+	case decodeUint:
+		parse, parsedType = "strconv.ParseUint("+src+", 10, "+bits+")", "uint64"
+	case decodeFloat:
+		parse, parsedType = "strconv.ParseFloat("+src+", "+bits+")", "float64"
+	case decodeDuration:
+		parse, parsedType = "time.ParseDuration("+src+")", "time.Duration"
+	}
+
+	if t.Kind == decodeDuration {
+		d.imports["time"] = true
+	} else {
+		d.imports["strconv"] = true
+	}
+
+	value := "parsed"
+	if t.Expr != parsedType {
+		value = t.Expr + "(parsed)"
+	}
+
+	fmt.Fprintf(b, "%sparsed, err := %s\n", indent, parse)
+	fmt.Fprintf(b, "%sif err != nil {\n%s\treturn false\n%s}\n", indent, indent, indent)
+	fmt.Fprintf(b, "%s%s = %s\n", indent, dst, value)
+}
+
+// generateYAML writes decodeYAML, which follows yaml.v3 and the `default` tag
+// handling of ParseYaml for plain YAML. Anything else, such as aliases, explicit
+// tags or numbers that are not decimal, is left to ParseYaml.
+func (d *decoder) generateYAML(b *bytes.Buffer) {
+	d.imports["os"] = true
+	d.imports["gopkg.in/yaml.v3"] = true
+	d.helpers["goconfDecodeMapping"] = true
+
+	fmt.Fprintf(b, `
+// decodeYAML loads the YAML file into c without reflection. It returns false
+// if the file cannot be decoded, goconf.ParseYaml then reports the error.
+func (c *%s) decodeYAML(file string) bool {
+	if goconf.CurrentYAMLKeyMatching() != goconf.YAMLKeyMatchingExact {
+		return false
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) != 1 {
+		return false
+	}
+`, d.config.TypeName)
+
+	b.WriteString("\n\tif !goconfDecodeMapping(doc.Content[0], func(key string, value *yaml.Node) bool {\n")
+	d.yamlMapping(b, d.fields, "\t\t")
+	b.WriteString("\t}) {\n\t\treturn false\n\t}\n\n")
+
+	b.WriteString("\treturn true\n}\n")
+}
+
+// yamlMapping writes the body of the function decoding the keys of a mapping
+func (d *decoder) yamlMapping(b *bytes.Buffer, fields []decoderField, indent string) {
+	fmt.Fprintf(b, "%sswitch key {\n", indent)
+
+	for _, f := range fields {
+		if !f.Exported || f.YAMLKey == "" {
+			continue
+		}
+
+		fmt.Fprintf(b, "%scase %q:\n", indent, f.YAMLKey)
+
+		switch {
+		case f.Type.Kind == decodeStruct:
+			fmt.Fprintf(b, "%s\treturn goconfDecodeMapping(value, func(key string, value *yaml.Node) bool {\n", indent)
+			d.yamlMapping(b, f.Fields, indent+"\t\t")
+			fmt.Fprintf(b, "%s\t})\n", indent)
+		default:
+			d.parseYAMLValue(b, f.Type, "value", f.Expr, indent+"\t")
+		}
+	}
+
+	fmt.Fprintf(b, "%s}\n\n%sreturn true\n", indent, indent)
+}
+
+// parseYAMLValue writes the decoding of the node src into dst
+func (d *decoder) parseYAMLValue(b *bytes.Buffer, t decoderType, src, dst, indent string) {
+	check := func(helper string, args ...string) {
+		d.helpers[helper] = true
+		fmt.Fprintf(b, "%sif !%s(%s) {\n%s\treturn false\n%s}\n", indent, helper, strings.Join(args, ", "), indent, indent)
+	}
+
+	switch t.Kind {
+	case decodeSlice:
+		check("goconfIsSequence", src)
+		fmt.Fprintf(b, "%sitems := make(%s, len(%s.Content))\n", indent, t.Expr, src)
+		fmt.Fprintf(b, "%sfor i, item := range %s.Content {\n", indent, src)
+		d.parseYAMLValue(b, *t.Elem, "item", "items[i]", indent+"\t")
+		fmt.Fprintf(b, "%s}\n", indent)
+		fmt.Fprintf(b, "%s%s = items\n", indent, dst)
+	case decodeString, decodeDuration:
+		check("goconfIsScalar", src, `"!!str"`)
+		d.parseScalar(b, t, src+".Value", dst, indent, "")
+	case decodeBool:
+		check("goconfIsScalar", src, `"!!bool"`)
+		d.parseScalar(b, t, src+".Value", dst, indent, "")
+	case decodeInt, decodeUint:
+		check("goconfIsInt", src)
+
+		bits := strconv.Itoa(t.Bits)
+		if t.Bits == 0 {
+			bits = "strconv.IntSize"
+		}
+
+		d.parseScalar(b, t, src+".Value", dst, indent, bits)
+	case decodeFloat:
+		check("goconfIsFloat", src)
+		d.imports["strconv"] = true
+
+		// yaml.v3 parses floats with 64 bits and rejects values overflowing float32
+		fmt.Fprintf(b, "%sparsed, err := strconv.ParseFloat(%s.Value, 64)\n", indent, src)
+		if t.Bits == 32 {
+			d.imports["math"] = true
+			fmt.Fprintf(b, "%sif err != nil || math.Abs(parsed) > math.MaxFloat32 {\n", indent)
+		} else {
+			fmt.Fprintf(b, "%sif err != nil {\n", indent)
+		}
+		fmt.Fprintf(b, "%s\treturn false\n%s}\n", indent, indent)
+
+		value := "parsed"
+		if t.Expr != "float64" {
+			value = t.Expr + "(parsed)"
+		}
+
+		fmt.Fprintf(b, "%s%s = %s\n", indent, dst, value)
+	}
+}
+
+// validateOperators are the comparison operators of the validate rules supported
+// by valid, other rules are left to goconf.StructValidator
+var validateOperators = map[string]string{
+	"gt": ">", "gte": ">=", "lt": "<", "lte": "<=", "min": ">=", "max": "<=", "len": "==", "eq": "==", "ne": "!=",
+}
+
+// negatedOperators negate the comparison operators of integers and strings
+var negatedOperators = map[string]string{
+	">": "<=", ">=": "<", "<": ">=", "<=": ">", "==": "!=", "!=": "==",
+}
+
+// oneofParams splits the parameter of the oneof rule like the validator
+var oneofParams = regexp.MustCompile(`'[^']*'|\S+`)
+
+// generateValid writes valid if all validate tags consist of rules with known
+// semantics, and reports whether it did
+func (d *decoder) generateValid(b *bytes.Buffer) bool {
+	var checks bytes.Buffer
+
+	ok := true
+	walkExported(d.fields, func(f decoderField) {
+		ok = ok && d.validChecks(&checks, f)
+	})
+
+	if !ok {
+		return false
+	}
+
+	fmt.Fprintf(b, `
+// valid reports whether c satisfies its validate tags, goconf.StructValidator
+// reports the errors otherwise
+func (c *%s) valid() bool {
+%s	return true
+}
+`, d.config.TypeName, checks.String())
+
+	return true
+}
+
+// validChecks writes the checks of the validate tag of a field and reports
+// whether all of its rules are supported
+func (d *decoder) validChecks(b *bytes.Buffer, f decoderField) bool {
+	tag := f.Tag.Get("validate")
+	if tag == "" {
+		return true
+	}
+
+	// Struct rules, alternatives and escaped separators are left to the validator
+	if f.Type.Kind == decodeStruct || strings.Contains(tag, "|") ||
+		strings.Contains(tag, "0x2C") || strings.Contains(tag, "0x7C") {
+		return false
+	}
+
+	rules := strings.Split(tag, ",")
+	omitEmpty := rules[0] == "omitempty"
+	if omitEmpty {
+		rules = rules[1:]
+	}
+
+	var failures []string
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+
+		failure, ok := d.validFailure(f, name, param)
+		if !ok {
+			return false
+		}
+
+		failures = append(failures, failure)
+	}
+
+	if len(failures) == 0 {
+		return true
+	}
+
+	indent := "\t"
+	if omitEmpty {
+		fmt.Fprintf(b, "\tif %s {\n", d.nonZeroExpr(f))
+		indent = "\t\t"
+	}
+
+	for _, failure := range failures {
+		fmt.Fprintf(b, "%sif %s {\n%s\treturn false\n%s}\n", indent, failure, indent, indent)
+	}
+
+	if omitEmpty {
+		b.WriteString("\t}\n")
+	}
+
+	b.WriteString("\n")
+
+	return true
+}
+
+// validFailure returns the condition failing a single validate rule, following
+// the validator's parsing of parameters
+func (d *decoder) validFailure(f decoderField, rule, param string) (string, bool) {
+	switch {
+	case rule == "required" && param == "":
+		return d.zeroExpr(f), true
+	case rule == "oneof" && param != "":
+		return d.oneofFailure(f, param)
+	case validateOperators[rule] == "" || param == "":
+		return "", false
+	}
+
+	op := negatedOperators[validateOperators[rule]]
+
+	switch f.Type.Kind {
+	case decodeString:
+		if rule == "eq" || rule == "ne" {
+			return f.Expr + " " + op + " " + strconv.Quote(param), true
+		}
+
+		p, err := strconv.ParseInt(param, 0, 64)
+		d.imports["unicode/utf8"] = true
+
+		return fmt.Sprintf("int64(utf8.RuneCountInString(%s)) %s %d", stringExpr(f), op, p), err == nil
+	case decodeSlice:
+		p, err := strconv.ParseInt(param, 0, 64)
+
+		return fmt.Sprintf("int64(len(%s)) %s %d", f.Expr, op, p), err == nil
+	case decodeInt, decodeDuration:
+		p, err := strconv.ParseInt(param, 0, 64)
+		if f.Type.Kind == decodeDuration {
+			if duration, durationErr := time.ParseDuration(param); durationErr == nil {
+				p, err = int64(duration), nil
+			}
+		}
+
+		return fmt.Sprintf("int64(%s) %s %d", f.Expr, op, p), err == nil
+	case decodeUint:
+		p, err := strconv.ParseUint(param, 0, 64)
+
+		return fmt.Sprintf("uint64(%s) %s %d", f.Expr, op, p), err == nil
+	case decodeFloat:
+		p, err := strconv.ParseFloat(param, f.Type.Bits)
+		if err != nil || math.IsInf(p, 0) || math.IsNaN(p) {
+			return "", false
+		}
+
+		// Negating the comparison keeps NaN failing every rule
+		return fmt.Sprintf("!(float64(%s) %s %s)", f.Expr, validateOperators[rule], strconv.FormatFloat(p, 'g', -1, 64)), true
+	case decodeBool:
+		p, err := strconv.ParseBool(param)
+		if err != nil || (rule != "eq" && rule != "ne") {
+			return "", false
+		}
+
+		if p == (rule == "eq") {
+			return "!" + f.Expr, true
+		}
+
+		return f.Expr, true
+	}
+
+	return "", false
+}
+
+// oneofFailure returns the condition failing the oneof rule, which compares the
+// decimal representation of numbers
+func (d *decoder) oneofFailure(f decoderField, param string) (string, bool) {
+	var failures []string
+
+	for _, value := range oneofParams.FindAllString(param, -1) {
+		value = strings.ReplaceAll(value, "'", "")
+
+		switch f.Type.Kind {
+		case decodeString:
+			failures = append(failures, f.Expr+" != "+strconv.Quote(value))
+		case decodeInt:
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(i, 10) == value {
+				failures = append(failures, fmt.Sprintf("int64(%s) != %d", f.Expr, i))
+			}
+		case decodeUint:
+			if u, err := strconv.ParseUint(value, 10, 64); err == nil && strconv.FormatUint(u, 10) == value {
+				failures = append(failures, fmt.Sprintf("uint64(%s) != %d", f.Expr, u))
+			}
+		default:
+			return "", false
+		}
+	}
+
+	if len(failures) == 0 {
+		return "true", true
+	}
+
+	return strings.Join(failures, " && "), true
+}
+
+// stringExpr returns the field converted to string if it has a named string type
+func stringExpr(f decoderField) string {
+	if f.Type.Expr == "string" {
+		return f.Expr
+	}
+
+	return "string(" + f.Expr + ")"
+}
+
+// generateListFields writes ListFields, which lists the fields like goconf does
+// with reflection, and the array holding the field types
+func (d *decoder) generateListFields(b *bytes.Buffer) {
+	d.imports["reflect"] = true
+	d.helpers["goconfKey"] = true
+
+	var body bytes.Buffer
+	d.listFields(&body, d.fields, 0, "fields", `""`)
+
+	typesVar := d.config.Loaded + "Types"
+
+	fmt.Fprintf(b, `
+// ListFields lists the fields of the %[1]s for printing without reflection
+func (c *%[1]s) ListFields(naming goconf.KeyNaming) []goconf.Field {
+	fields := make([]goconf.Field, 0, %[2]d)
+%[3]s
+	return fields
+}
+
+// %[4]s holds the types of the fields listed by ListFields
+var %[4]s = [...]reflect.Type{
+`, d.config.TypeName, exportedCount(d.fields), body.String(), typesVar)
+
+	for _, t := range d.types {
+		fmt.Fprintf(b, "\treflect.TypeOf(%s),\n", t)
+	}
+
+	b.WriteString("}\n")
+}
+
+func exportedCount(fields []decoderField) int {
+	n := 0
+	for _, f := range fields {
+		if f.Exported {
+			n++
+		}
+	}
+
+	return n
+}
+
+// listFields writes the blocks appending the fields of a struct to list, prefix
+// is the expression of the key of the struct
+func (d *decoder) listFields(b *bytes.Buffer, fields []decoderField, depth int, list, prefix string) {
+	indent := strings.Repeat("\t", depth+1)
+	v := "f" + strconv.Itoa(depth)
+
+	for _, f := range fields {
+		if !f.Exported {
+			continue
+		}
+
+		d.types = append(d.types, f.Zero)
+		isStruct := f.Type.Kind == decodeStruct && !f.Secret
+
+		fmt.Fprintf(b, "\n%s{\n%s\t%s := goconf.Field{\n", indent, indent, v)
+		field := func(name, value string) {
+			fmt.Fprintf(b, "%s\t\t%s: %s,\n", indent, name, value)
+		}
+
+		paths := make([]string, len(f.Path))
+		for i, p := range f.Path {
+			paths[i] = strconv.Quote(p)
+		}
+
+		field("Path", "[]string{"+strings.Join(paths, ", ")+"}")
+		if !isStruct {
+			field("Value", f.Expr)
+		}
+		field("Type", fmt.Sprintf("%sTypes[%d]", d.config.Loaded, len(d.types)-1))
+		if f.Env != "" {
+			field("Source", strconv.Quote(f.Env))
+		}
+		if f.Tag != "" {
+			field("Tags", tagLiteral(f.Tag))
+		}
+		if f.Secret {
+			field("Secret", "true")
+		}
+		if desc := f.Tag.Get("desc"); desc != "" {
+			field("Description", strconv.Quote(desc))
+		}
+
+		raw, hasDefault := f.EnvDefault, f.HasEnvDefault
+		if !hasDefault {
+			raw, hasDefault = f.Default, f.HasDefault
+		}
+
+		if hasDefault {
+			if raw != "" {
+				field("Default", strconv.Quote(raw))
+			}
+			field("HasDefault", "true")
+			field("IsDefault", d.isDefaultExpr(f, raw))
+		} else {
+			field("IsDefault", d.zeroExpr(f))
+		}
+
+		fmt.Fprintf(b, "%s\t}\n", indent)
+		fmt.Fprintf(b, "%s\t%s.Name, %s.Key = goconfKey(naming, %s, %q, %q, %q, %q)\n",
+			indent, v, v, prefix, f.Name, tagName(f.Tag, "yaml"), tagName(f.Tag, "json"), f.Env)
+
+		if isStruct {
+			fmt.Fprintf(b, "%s\t%s.Fields = make([]goconf.Field, 0, %d)\n", indent, v, exportedCount(f.Fields))
+			d.listFields(b, f.Fields, depth+1, v+".Fields", v+".Key")
+		}
+
+		fmt.Fprintf(b, "\n%s\t%s = append(%s, %s)\n%s}\n", indent, list, list, v, indent)
+	}
+}
+
+// isDefaultExpr returns an expression reporting whether a field equals its
+// default parsed like ApplyDefaults parses it
+func (d *decoder) isDefaultExpr(f decoderField, raw string) string {
+	literal := mustLiteral(d.defaultLiteral(f.Type, raw))
+
+	switch {
+	case literal == "nil":
+		return f.Expr + " == nil"
+	case f.Type.Kind == decodeSlice:
+		d.imports["slices"] = true
+		return "slices.Equal(" + f.Expr + ", " + literal + ")"
+	case literal == "true":
+		return f.Expr
+	case literal == "false":
+		return "!" + f.Expr
+	default:
+		return f.Expr + " == " + literal
+	}
+}
+
+// tagLiteral returns the Go literal of a struct tag, a raw string if possible
+func tagLiteral(tag reflect.StructTag) string {
+	if strings.Contains(string(tag), "`") {
+		return strconv.Quote(string(tag))
+	}
+
+	return "`" + string(tag) + "`"
+}
+
+// tagName returns the name part of a struct tag, empty if it is missing or "-"
+func tagName(tag reflect.StructTag, key string) string {
+	name, _, _ := strings.Cut(tag.Get(key), ",")
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+// decoderHelpers are the helper functions of the generated decoders in the
+// order they are written
+var decoderHelpers = []struct {
+	name    string
+	imports []string
+	code    string
+}{
+	{"goconfKey", nil, `
+// goconfKey returns the name and key of a field in a key naming mode
+func goconfKey(naming goconf.KeyNaming, prefix, field, yamlName, jsonName, env string) (string, string) {
+	name := field
+
+	switch {
+	case naming == goconf.KeyNamingEnv && env != "":
+		return env, env
+	case naming == goconf.KeyNamingYAML && yamlName != "":
+		name = yamlName
+	case naming == goconf.KeyNamingJSON && jsonName != "":
+		name = jsonName
+	}
+
+	if prefix == "" {
+		return name, name
+	}
+
+	return name, prefix + "." + name
+}
+`},
+	{"goconfDecodeMapping", []string{"gopkg.in/yaml.v3"}, `
+// goconfDecodeMapping calls decode for the values of a mapping with plain and
+// unique string keys and reports whether all of them were decoded
+func goconfDecodeMapping(node *yaml.Node, decode func(key string, value *yaml.Node) bool) bool {
+	if node.Kind != yaml.MappingNode || node.ShortTag() != "!!map" {
+		return false
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !goconfIsScalar(key, "!!str") {
+			return false
+		}
+
+		for j := 0; j < i; j += 2 {
+			if node.Content[j].Value == key.Value {
+				return false
+			}
+		}
+
+		if !decode(key.Value, node.Content[i+1]) {
+			return false
+		}
+	}
+
+	return true
+}
+`},
+	{"goconfIsSequence", []string{"gopkg.in/yaml.v3"}, `
+// goconfIsSequence reports whether node is a sequence without an explicit tag
+func goconfIsSequence(node *yaml.Node) bool {
+	return node.Kind == yaml.SequenceNode && node.ShortTag() == "!!seq"
+}
+`},
+	{"goconfIsScalar", []string{"gopkg.in/yaml.v3"}, `
+// goconfIsScalar reports whether node is a scalar with the given tag resolved
+// from its value rather than set explicitly
+func goconfIsScalar(node *yaml.Node, tag string) bool {
+	return node.Kind == yaml.ScalarNode && node.Style&yaml.TaggedStyle == 0 && node.ShortTag() == tag
+}
+`},
+	{"goconfIsInt", []string{"gopkg.in/yaml.v3", "strings"}, `
+// goconfIsInt reports whether node is a decimal integer without leading zeros,
+// which yaml.v3 reads like strconv.ParseInt with base 10
+func goconfIsInt(node *yaml.Node) bool {
+	if !goconfIsScalar(node, "!!int") {
+		return false
+	}
+
+	digits := strings.TrimLeft(node.Value, "+-")
+	if digits == "" || len(node.Value)-len(digits) > 1 || (len(digits) > 1 && digits[0] == '0') {
+		return false
+	}
+
+	return strings.Trim(digits, "0123456789") == ""
+}
+`},
+	{"goconfIsFloat", []string{"gopkg.in/yaml.v3", "strings"}, `
+// goconfIsFloat reports whether node is a decimal number, which yaml.v3 reads
+// like strconv.ParseFloat
+func goconfIsFloat(node *yaml.Node) bool {
+	return goconfIsInt(node) || goconfIsScalar(node, "!!float") && !strings.Contains(node.Value, "_")
+}
+`},
+}
+
+// helperNames returns the names of all helper functions
+func helperNames() []string {
+	names := make([]string, len(decoderHelpers))
+	for i, helper := range decoderHelpers {
+		names[i] = helper.name
+	}
+
+	return names
+}
+
+// code returns the helper functions used by the decoders of the file
+func (file *decoderFile) code() string {
+	if file.helpers["goconfIsFloat"] {
+		file.helpers["goconfIsInt"] = true
+	}
+
+	if file.helpers["goconfIsInt"] || file.helpers["goconfDecodeMapping"] {
+		file.helpers["goconfIsScalar"] = true
+	}
+
+	var b strings.Builder
+	for _, helper := range decoderHelpers {
+		if !file.helpers[helper.name] {
+			continue
+		}
+
+		b.WriteString(helper.code)
+		for _, path := range helper.imports {
+			file.imports[path] = true
+		}
+	}
+
+	return b.String()
+}
+
+// importPaths returns the imports of the file besides goconf, split into the
+// standard library and other modules
+func (file *decoderFile) importPaths() ([]string, []string) {
+	var std, other []string
+	for path := range file.imports {
+		if strings.Contains(path, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+
+	sort.Strings(std)
+	sort.Strings(other)
+
+	return std, other
+}
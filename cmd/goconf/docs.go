@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wgarunap/goconf/spec"
+)
+
+// Markers delimiting the generated reference in an existing document, e.g. a README
+const (
+	docsBeginMarker = "<!-- goconf:begin -->"
+	docsEndMarker   = "<!-- goconf:end -->"
+)
+
+// runDocs writes a configuration reference for a config struct
+//
+//	goconf docs -type Config [-format markdown|html] [-o README.md] [-check] [-derive-env [-env-prefix APP_]] [packages]
+func runDocs(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("docs", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	typeName := flags.String("type", "", "name of the config struct")
+	format := flags.String("format", string(spec.ReferenceMarkdown), "output format, markdown or html")
+	output := flags.String("o", "", "output file, standard output if empty. If the file contains\n"+
+		docsBeginMarker+" and "+docsEndMarker+" only the text between them is replaced")
+	check := flags.Bool("check", false, "fail if the output file is not up to date instead of writing it")
+	applyEnvNaming := envNamingFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := applyEnvNaming(); err != nil {
+		return err
+	}
+
+	if *check && *output == "" {
+		return errors.New("the -check flag requires the -o flag")
+	}
+
+	fields, err := loadStruct(flags.Args(), *typeName)
+	if err != nil {
+		return err
+	}
+
+	var reference bytes.Buffer
+	if err := spec.WriteReference(&reference, fields, spec.ReferenceFormat(*format)); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err := stdout.Write(reference.Bytes())
+		return err
+	}
+
+	existing, err := os.ReadFile(*output)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	content, err := embedReference(string(existing), reference.String())
+	if err != nil {
+		return fmt.Errorf("%s: %w", *output, err)
+	}
+
+	if *check {
+		if content != string(existing) {
+			return fmt.Errorf("%s is out of date, run goconf docs to update it", *output)
+		}
+
+		return nil
+	}
+
+	return writeOutput(*output, stdout, func(w io.Writer) error {
+		_, err := io.WriteString(w, content)
+		return err
+	})
+}
+
+// embedReference replaces the text between the markers of document with the
+// reference. Without markers the reference replaces the whole document.
+func embedReference(document, reference string) (string, error) {
+	before, rest, hasBegin := strings.Cut(document, docsBeginMarker)
+	_, after, hasEnd := strings.Cut(rest, docsEndMarker)
+
+	switch {
+	case !hasBegin && !strings.Contains(document, docsEndMarker):
+		return reference, nil
+	case !hasBegin || !hasEnd:
+		return "", fmt.Errorf("%s must be followed by %s", docsBeginMarker, docsEndMarker)
+	}
+
+	return before + docsBeginMarker + "\n" + reference + docsEndMarker + after, nil
+}
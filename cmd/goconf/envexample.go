@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/wgarunap/goconf/spec"
+)
+
+// runEnvExample writes a commented .env.example for a config struct
+//
+//	goconf env-example -type Config [-o .env.example] [-derive-env [-env-prefix APP_]] [packages]
+func runEnvExample(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("env-example", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	typeName := flags.String("type", "", "name of the config struct")
+	output := flags.String("o", "", "output file, standard output if empty")
+	applyEnvNaming := envNamingFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := applyEnvNaming(); err != nil {
+		return err
+	}
+
+	fields, err := loadStruct(flags.Args(), *typeName)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, stdout, func(w io.Writer) error {
+		return spec.WriteEnvExample(w, fields)
+	})
+}
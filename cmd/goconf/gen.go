@@ -0,0 +1,478 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	// genDirective marks the structs goconf gen generates code for
+	genDirective = "//goconf:config"
+	// genHeader is the first line of the generated files
+	genHeader = "// Code generated by goconf gen. DO NOT EDIT."
+	// genFile is the default name of the generated file in the package directory
+	genFile = "goconf_gen.go"
+)
+
+// genSources are the sources a config can be loaded from
+var genSources = map[string]bool{"env": true, "yaml": true}
+
+// genConfig describes the code generated for a struct with a goconf:config directive
+type genConfig struct {
+	TypeName string
+	// Sources are the sources in load order, later sources override earlier ones
+	Sources []string
+	// File is the YAML file
+	File   string
+	Getter string
+	Loaded string
+	// Generated selects the generated decoders instead of reflection
+	Generated bool
+}
+
+// genStep loads a source with the generated decoder, falling back to goconf
+type genStep struct {
+	Decode string
+	Parse  string
+}
+
+// Steps returns the decoder and parse calls loading the sources in order
+func (c genConfig) Steps() []genStep {
+	calls := c.Calls()
+	steps := make([]genStep, len(c.Sources))
+	for i, source := range c.Sources {
+		steps[i].Parse = calls[i]
+		if source == "yaml" {
+			steps[i].Decode = "decodeYAML(" + strconv.Quote(c.File) + ")"
+		} else {
+			steps[i].Decode = "decodeEnv()"
+		}
+	}
+
+	return steps
+}
+
+// Calls returns the parse calls loading the sources in order
+func (c genConfig) Calls() []string {
+	calls := make([]string, len(c.Sources))
+	for i, source := range c.Sources {
+		if source == "yaml" {
+			calls[i] = "goconf.ParseYaml(c, " + strconv.Quote(c.File) + ")"
+		} else {
+			calls[i] = "goconf.ParseEnv(c)"
+		}
+	}
+
+	return calls
+}
+
+// SourceNames describes the sources in order for the generated doc comments
+func (c genConfig) SourceNames() string {
+	names := make([]string, len(c.Sources))
+	for i, source := range c.Sources {
+		if source == "yaml" {
+			names[i] = c.File
+		} else {
+			names[i] = "the environment"
+		}
+	}
+
+	return strings.Join(names, " and then ")
+}
+
+// genCode is a config with the code of its generated decoders
+type genCode struct {
+	genConfig
+	// Decoders holds the methods of the generated decoders
+	Decoders string
+	// Valid reports whether the generated valid method checks the validate tags
+	Valid bool
+}
+
+var genTemplate = template.Must(template.New("gen").Funcs(template.FuncMap{
+	"last": func(i int, values []string) bool {
+		return i == len(values)-1
+	},
+}).Parse(genHeader + `
+
+package {{.Package}}
+{{if .Imports}}
+import (
+{{- range $i, $group := .Imports}}
+{{- if $i}}
+{{end}}
+{{- range $group}}
+	"{{.}}"
+{{- end}}
+{{- end}}
+)
+{{else}}
+import "github.com/wgarunap/goconf"
+{{end}}
+{{- range .Configs}}
+var (
+	_ goconf.Configer  = (*{{.TypeName}})(nil)
+	_ goconf.Validater = (*{{.TypeName}})(nil)
+	_ goconf.Printer   = (*{{.TypeName}})(nil)
+{{- if .Generated}}
+	_ goconf.FieldLister = (*{{.TypeName}})(nil)
+{{- end}}
+)
+
+// {{.Loaded}} is the {{.TypeName}} registered with goconf.Load
+var {{.Loaded}} *{{.TypeName}}
+{{if .Generated}}
+// Register loads the {{.TypeName}} from {{.SourceNames}}.
+// Input the generated decoders do not handle is loaded by goconf.
+func (c *{{.TypeName}}) Register() error {
+	{{.Loaded}} = c
+{{range .Steps}}
+	if decoded := *c; decoded.{{.Decode}} {
+		*c = decoded
+	} else if err := {{.Parse}}; err != nil {
+		return err
+	}
+{{end}}
+	return nil
+}
+{{else}}
+// Register loads the {{.TypeName}} from {{.SourceNames}}
+func (c *{{.TypeName}}) Register() error {
+	{{.Loaded}} = c
+{{$calls := .Calls}}{{range $i, $call := $calls}}
+{{- if last $i $calls}}
+	return {{$call}}
+{{- else}}
+	if err := {{$call}}; err != nil {
+		return err
+	}
+{{end}}
+{{- end}}
+}
+{{end}}
+// Validate validates the {{.TypeName}} against its validate tags
+func (c *{{.TypeName}}) Validate() error {
+{{- if .Valid}}
+	if !goconf.DefaultValidator().IsCustomized() && c.valid() {
+		return nil
+	}
+
+{{end}}
+	return goconf.StructValidator(c)
+}
+
+// Print returns the {{.TypeName}} to print, secret fields are masked
+func (c *{{.TypeName}}) Print() interface{} {
+	return *c
+}
+
+// {{.Getter}} returns a copy of the {{.TypeName}} loaded by goconf.Load, or the
+// zero value if it has not been registered
+func {{.Getter}}() {{.TypeName}} {
+	if {{.Loaded}} == nil {
+		return {{.TypeName}}{}
+	}
+
+	return *{{.Loaded}}
+}
+{{.Decoders}}
+{{- end}}
+{{- .Helpers}}`))
+
+// runGen generates the Register, Validate and Print methods and a typed getter
+// for the structs with a goconf:config directive
+//
+//	goconf gen [-o goconf_gen.go] [packages]
+func runGen(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("o", genFile, "name of the generated file in the package directory, - for standard output")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	pkgs, err := loadPackages(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	generated := 0
+	for _, pkg := range pkgs {
+		configs, err := genConfigs(pkg)
+		if err != nil {
+			return err
+		}
+
+		if len(configs) == 0 {
+			continue
+		}
+
+		code, err := generate(pkg, configs)
+		if err != nil {
+			return err
+		}
+
+		if *output == "-" {
+			if _, err := stdout.Write(code); err != nil {
+				return err
+			}
+		} else if err := os.WriteFile(filepath.Join(filepath.Dir(pkg.GoFiles[0]), *output), code, 0o644); err != nil {
+			return err
+		}
+
+		generated++
+	}
+
+	if generated == 0 {
+		return fmt.Errorf("no %s directive found", genDirective)
+	}
+
+	return nil
+}
+
+// genConfigs returns the structs of the package with a goconf:config directive
+func genConfigs(pkg *packages.Package) ([]genConfig, error) {
+	var configs []genConfig
+
+	for _, file := range pkg.Syntax {
+		if isGenerated(file) {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, s := range gen.Specs {
+				spec := s.(*ast.TypeSpec)
+
+				doc := spec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+
+				directive := findDirective(doc)
+				if directive == nil {
+					continue
+				}
+
+				config, err := parseDirective(spec.Name.Name, directive.Text)
+				if err == nil {
+					err = checkDeclarations(pkg, config)
+				}
+				if err == nil && config.Generated {
+					_, err = newDecoder(pkg.Types, config, newDecoderFile())
+				}
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", pkg.Fset.Position(directive.Pos()), err)
+				}
+
+				configs = append(configs, config)
+			}
+		}
+	}
+
+	return configs, nil
+}
+
+// findDirective returns the goconf:config comment of a doc comment
+func findDirective(doc *ast.CommentGroup) *ast.Comment {
+	if doc == nil {
+		return nil
+	}
+
+	for _, c := range doc.List {
+		if c.Text == genDirective || strings.HasPrefix(c.Text, genDirective+" ") {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// parseDirective parses the options of a directive, e.g.
+// //goconf:config source=env,yaml file=config.yaml getter=Settings decoder=generated
+func parseDirective(typeName, directive string) (genConfig, error) {
+	config := genConfig{
+		TypeName: typeName,
+		Sources:  []string{"env"},
+		Getter:   exportedName("get", typeName),
+		Loaded:   "loaded" + upperFirst(typeName),
+	}
+
+	for _, option := range strings.Fields(strings.TrimPrefix(directive, genDirective)) {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok || value == "" {
+			return config, fmt.Errorf("invalid option %q, options are written as key=value", option)
+		}
+
+		switch key {
+		case "source":
+			config.Sources = strings.Split(value, ",")
+		case "file":
+			config.File = value
+		case "getter":
+			if !token.IsIdentifier(value) {
+				return config, fmt.Errorf("invalid getter name %q", value)
+			}
+			config.Getter = value
+		case "decoder":
+			if value != "generated" && value != "reflect" {
+				return config, fmt.Errorf("unknown decoder %q, the decoders are generated and reflect", value)
+			}
+			config.Generated = value == "generated"
+		default:
+			return config, fmt.Errorf("unknown option %q", key)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, source := range config.Sources {
+		if !genSources[source] {
+			return config, fmt.Errorf("unknown source %q, the sources are env and yaml", source)
+		}
+
+		if seen[source] {
+			return config, fmt.Errorf("source %q is listed twice", source)
+		}
+		seen[source] = true
+	}
+
+	if seen["yaml"] != (config.File != "") {
+		return config, errors.New("the file option is required with the yaml source, and only allowed with it")
+	}
+
+	return config, nil
+}
+
+// checkDeclarations checks that the config is a struct and the generated
+// declarations do not conflict with the declarations of the package
+func checkDeclarations(pkg *packages.Package, config genConfig) error {
+	obj := pkg.Types.Scope().Lookup(config.TypeName)
+	if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+		return fmt.Errorf("%s is not a struct", config.TypeName)
+	}
+
+	// Declarations of an earlier run are ignored, since the file is replaced
+	methods := types.NewMethodSet(types.NewPointer(obj.Type()))
+	names := []string{"Register", "Validate", "Print"}
+	if config.Generated {
+		names = append(names, decoderMethods...)
+	}
+
+	for _, name := range names {
+		if sel := methods.Lookup(pkg.Types, name); sel != nil && !declaredInGenerated(pkg, sel.Obj()) {
+			return fmt.Errorf("%s already declares %s, remove it to generate it", config.TypeName, name)
+		}
+	}
+
+	names = []string{config.Getter, config.Loaded}
+	if config.Generated {
+		names = append(append(names, config.Loaded+"Types"), helperNames()...)
+	}
+
+	for _, name := range names {
+		if existing := pkg.Types.Scope().Lookup(name); existing != nil && !declaredInGenerated(pkg, existing) {
+			return fmt.Errorf("%s is already declared in package %s", name, pkg.Name)
+		}
+	}
+
+	return nil
+}
+
+// generate returns the formatted code of the configs of a package
+func generate(pkg *packages.Package, configs []genConfig) ([]byte, error) {
+	file := newDecoderFile()
+	codes := make([]genCode, len(configs))
+	for i, config := range configs {
+		codes[i].genConfig = config
+		if !config.Generated {
+			continue
+		}
+
+		d, err := newDecoder(pkg.Types, config, file)
+		if err != nil {
+			return nil, err
+		}
+
+		codes[i].Decoders, codes[i].Valid = d.generate()
+	}
+
+	helpers := file.code()
+
+	// The single goconf import is kept unless the decoders import more
+	var imports [][]string
+	if std, other := file.importPaths(); len(std) > 0 || len(other) > 0 {
+		imports = [][]string{std, append(other, "github.com/wgarunap/goconf")}
+		sort.Strings(imports[1])
+	}
+
+	var b bytes.Buffer
+	err := genTemplate.Execute(&b, map[string]interface{}{
+		"Package": pkg.Name,
+		"Imports": imports,
+		"Configs": codes,
+		"Helpers": helpers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return code, nil
+}
+
+// isGenerated reports whether the file was written by goconf gen
+func isGenerated(file *ast.File) bool {
+	return len(file.Comments) > 0 && file.Comments[0].List[0].Text == genHeader
+}
+
+// declaredInGenerated reports whether obj is declared in a file written by goconf gen
+func declaredInGenerated(pkg *packages.Package, obj types.Object) bool {
+	for _, file := range pkg.Syntax {
+		if file.FileStart <= obj.Pos() && obj.Pos() < file.FileEnd {
+			return isGenerated(file)
+		}
+	}
+
+	return false
+}
+
+// exportedName joins prefix and name, exported if name is exported
+func exportedName(prefix, name string) string {
+	if ast.IsExported(name) {
+		return upperFirst(prefix) + name
+	}
+
+	return prefix + upperFirst(name)
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/wgarunap/goconf/spec"
+)
+
+// runK8s writes a Kubernetes ConfigMap and Secret stub for a config struct, or
+// the env snippet of a Deployment container referencing them
+//
+//	goconf k8s -type Config -name my-app [-snippet envFrom|env] [-o config.yaml] [-derive-env [-env-prefix APP_]] [packages]
+func runK8s(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("k8s", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	typeName := flags.String("type", "", "name of the config struct")
+	name := flags.String("name", "", "name of the ConfigMap and Secret")
+	snippet := flags.String("snippet", "", "write the container env snippet instead of the manifests, envFrom or env")
+	output := flags.String("o", "", "output file, standard output if empty")
+	applyEnvNaming := envNamingFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := applyEnvNaming(); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return errors.New("the -name flag is required")
+	}
+
+	fields, err := loadStruct(flags.Args(), *typeName)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, stdout, func(w io.Writer) error {
+		if *snippet != "" {
+			return spec.WriteKubernetesEnv(w, fields, *name, spec.KubernetesEnvStyle(*snippet))
+		}
+
+		return spec.WriteKubernetesManifests(w, fields, *name)
+	})
+}
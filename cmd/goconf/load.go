@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/wgarunap/goconf"
+	"github.com/wgarunap/goconf/spec"
+)
+
+// envNamingFlags registers the flags deriving the env variable names of fields
+// without an env tag, and returns a function applying them with goconf.SetEnvNaming
+func envNamingFlags(flags *flag.FlagSet) func() error {
+	derive := flags.Bool("derive-env", false, "derive the env variable names of fields without an env tag\n"+
+		"from their field path, like goconf.SetEnvNaming")
+	prefix := flags.String("env-prefix", "", "prefix of the derived env variable names, e.g. APP_")
+
+	return func() error {
+		if *prefix != "" && !*derive {
+			return errors.New("the -env-prefix flag requires the -derive-env flag")
+		}
+
+		goconf.SetEnvNaming(goconf.EnvNaming{Derive: *derive, Prefix: *prefix})
+
+		return nil
+	}
+}
+
+// loadStruct type checks the packages matching patterns and describes the
+// struct named typeName declared in one of them
+func loadStruct(patterns []string, typeName string) ([]spec.Field, error) {
+	_, obj, err := findType(patterns, typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return spec.FromTypes(obj.Type())
+}
+
+// findType type checks the packages matching patterns and returns the type
+// named typeName together with the package declaring it
+func findType(patterns []string, typeName string) (*packages.Package, *types.TypeName, error) {
+	if typeName == "" {
+		return nil, nil, errors.New("the -type flag is required")
+	}
+
+	pkgs, err := loadPackages(patterns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var found []*packages.Package
+	var obj *types.TypeName
+	for _, pkg := range pkgs {
+		if o, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName); ok {
+			found = append(found, pkg)
+			obj = o
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		if len(patterns) == 0 {
+			patterns = []string{"."}
+		}
+
+		return nil, nil, fmt.Errorf("type %s not found in %s", typeName, strings.Join(patterns, " "))
+	case 1:
+		return found[0], obj, nil
+	default:
+		return nil, nil, fmt.Errorf("type %s is declared in more than one package", typeName)
+	}
+}
+
+// loadPackages type checks the packages matching patterns, the package in the
+// current directory if there are none
+func loadPackages(patterns []string) ([]*packages.Package, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	// Type checking from source does not depend on the export data format of the
+	// installed Go toolchain
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	var errs []string
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e.Error())
+		}
+	})
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to load packages:\n%s", strings.Join(errs, "\n"))
+	}
+
+	return pkgs, nil
+}
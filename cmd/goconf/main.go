@@ -0,0 +1,78 @@
+// Command goconf generates files from goconf configuration structs without
+// building or running the program that declares them.
+//
+// Usage:
+//
+//	goconf <command> [flags] [packages]
+//
+// The commands are:
+//
+//	check        validate a YAML file against a config struct
+//	docs         write a markdown or HTML configuration reference for a config struct
+//	env-example  write a commented .env.example for a config struct
+//	gen          generate the Register, Validate and Print methods of config structs
+//	k8s          write a Kubernetes ConfigMap and Secret for a config struct
+//	schema       write a JSON Schema of the YAML file of a config struct
+//
+// Run "goconf <command> -h" for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command runs a goconf subcommand with its arguments
+type command func(args []string, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"check":       runCheck,
+	"docs":        runDocs,
+	"env-example": runEnvExample,
+	"gen":         runGen,
+	"k8s":         runK8s,
+	"schema":      runSchema,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command named by the first argument and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "goconf: unknown command %q\n", args[0])
+		usage(stderr)
+
+		return 2
+	}
+
+	if err := cmd(args[1:], stdout, stderr); err != nil {
+		fmt.Fprintf(stderr, "goconf %s: %v\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: goconf <command> [flags] [packages]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", name)
+	}
+}
@@ -0,0 +1,586 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wgarunap/goconf"
+	"github.com/wgarunap/goconf/cmd/goconf/testdata/config"
+	"github.com/wgarunap/goconf/cmd/goconf/testdata/gen"
+	"github.com/wgarunap/goconf/spec"
+)
+
+const testPackage = "./testdata/config"
+
+func TestLoadStruct(t *testing.T) {
+	fields, err := loadStruct([]string{testPackage}, "Config")
+	require.NoError(t, err)
+
+	expected, err := spec.FromValue(config.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, fields)
+
+	_, err = loadStruct([]string{testPackage}, "Missing")
+	assert.EqualError(t, err, "type Missing not found in ./testdata/config")
+
+	_, err = loadStruct([]string{testPackage}, "")
+	assert.EqualError(t, err, "the -type flag is required")
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedStderr string
+	}{
+		{
+			name:           "no command",
+			expectedCode:   2,
+			expectedStderr: "usage: goconf <command> [flags] [packages]",
+		},
+		{
+			name:           "unknown command",
+			args:           []string{"unknown"},
+			expectedCode:   2,
+			expectedStderr: `goconf: unknown command "unknown"`,
+		},
+		{
+			name:           "command error",
+			args:           []string{"env-example", testPackage},
+			expectedCode:   1,
+			expectedStderr: "goconf env-example: the -type flag is required",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(test.args, &stdout, &stderr)
+
+			assert.Equal(t, test.expectedCode, code)
+			assert.Contains(t, stderr.String(), test.expectedStderr)
+		})
+	}
+}
+
+func TestEnvExample(t *testing.T) {
+	expected, err := os.ReadFile("testdata/config.env.example")
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run([]string{"env-example", "-type", "Config", testPackage}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, string(expected), stdout.String())
+
+	output := filepath.Join(t.TempDir(), ".env.example")
+	require.Equal(t, 0, run([]string{"env-example", "-type", "Config", "-o", output, testPackage}, &stdout, &stderr), stderr.String())
+
+	written, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(written))
+
+	defer goconf.SetEnvNaming(goconf.EnvNaming{})
+
+	stdout.Reset()
+	require.Equal(t, 0, run([]string{"env-example", "-type", "Config", "-derive-env", "-env-prefix", "SVC_", testPackage}, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "\nAPP_NAME=\"my app\"\n")
+	assert.Contains(t, stdout.String(), "\nSVC_INTERNAL=\n")
+	assert.Contains(t, stdout.String(), "\nSVC_STARTED=\n")
+	assert.Contains(t, stdout.String(), "\nDB_HOST=localhost\n")
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"env-example", "-type", "Config", "-env-prefix", "SVC_", testPackage}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "the -env-prefix flag requires the -derive-env flag")
+}
+
+func TestDocs(t *testing.T) {
+	reference, err := os.ReadFile("testdata/config.md")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	readme := filepath.Join(dir, "README.md")
+	require.NoError(t, os.WriteFile(readme, []byte("# Service\n\n"+docsBeginMarker+"\nstale\n"+docsEndMarker+"\n\nFooter\n"), 0o600))
+
+	docs := func(args ...string) (int, string) {
+		var stdout, stderr bytes.Buffer
+		code := run(append(append([]string{"docs", "-type", "Config"}, args...), testPackage), &stdout, &stderr)
+
+		return code, stdout.String() + stderr.String()
+	}
+
+	code, output := docs()
+	require.Equal(t, 0, code, output)
+	assert.Equal(t, string(reference), output)
+
+	code, output = docs("-check", "-o", readme)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, output, readme+" is out of date, run goconf docs to update it")
+
+	code, output = docs("-o", readme)
+	require.Equal(t, 0, code, output)
+
+	updated, err := os.ReadFile(readme)
+	require.NoError(t, err)
+	assert.Equal(t, "# Service\n\n"+docsBeginMarker+"\n"+string(reference)+docsEndMarker+"\n\nFooter\n", string(updated))
+
+	code, output = docs("-check", "-o", readme)
+	assert.Equal(t, 0, code, output)
+
+	code, output = docs("-check")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, output, "the -check flag requires the -o flag")
+}
+
+func TestEmbedReference(t *testing.T) {
+	content, err := embedReference("", "table\n")
+	require.NoError(t, err)
+	assert.Equal(t, "table\n", content)
+
+	content, err = embedReference("a "+docsBeginMarker+" old "+docsEndMarker+" b", "table\n")
+	require.NoError(t, err)
+	assert.Equal(t, "a "+docsBeginMarker+"\ntable\n"+docsEndMarker+" b", content)
+
+	_, err = embedReference(docsBeginMarker, "table\n")
+	assert.EqualError(t, err, docsBeginMarker+" must be followed by "+docsEndMarker)
+
+	_, err = embedReference(docsEndMarker+docsBeginMarker, "table\n")
+	assert.Error(t, err)
+}
+
+func TestSchema(t *testing.T) {
+	expected, err := os.ReadFile("testdata/config.schema.json")
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run([]string{"schema", "-type", "Config", testPackage}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, string(expected), stdout.String())
+}
+
+func TestCheck(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check", "-type", "Config", "-f", "testdata/invalid.yaml", "-env", "testdata/prod.env", testPackage}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, `testdata/invalid.yaml:2:1: unknown key "prot"
+testdata/invalid.yaml:3:1: Port must be 1,024 or greater
+testdata/invalid.yaml:5:3: Host is a required field
+testdata/prod.env:2: invalid value for RETRIES: strconv.ParseInt: parsing "three": invalid syntax
+`, stdout.String())
+	assert.Equal(t, "goconf check: found 4 problems in testdata/invalid.yaml\n", stderr.String())
+
+	stdout.Reset()
+	stderr.Reset()
+	require.Equal(t, 0, run([]string{"check", "-type", "Config", "-f", "testdata/valid.yaml", testPackage}, &stdout, &stderr), stderr.String())
+	assert.Empty(t, stdout.String())
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"check", "-type", "Config", testPackage}, &stdout, &stderr))
+	assert.Equal(t, "goconf check: the -f flag is required\n", stderr.String())
+}
+
+func TestCheckOptions(t *testing.T) {
+	defer goconf.SetEnvNaming(goconf.EnvNaming{})
+
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte("Name: my app\nport: 8080\ndatabase:\n  host: db.local\n"), 0o600))
+	envFile := filepath.Join(dir, "derived.env")
+	require.NoError(t, os.WriteFile(envFile, []byte("SVC_STARTED=yesterday\n"), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"check", "-type", "Config", "-f", yamlFile, testPackage}, &stdout, &stderr))
+	assert.Equal(t, yamlFile+":1:1: unknown key \"Name\"\n"+yamlFile+": Name is a required field\n", stdout.String())
+
+	stdout.Reset()
+	stderr.Reset()
+	require.Equal(t, 0, run([]string{"check", "-type", "Config", "-f", yamlFile, "-loose-keys", testPackage}, &stdout, &stderr), stderr.String())
+	assert.Empty(t, stdout.String())
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"check", "-type", "Config", "-f", yamlFile, "-env", envFile, "-loose-keys",
+		"-derive-env", "-env-prefix", "SVC_", testPackage}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), envFile+":1: invalid value for SVC_STARTED")
+
+	// The check program is built from a temporary directory, nothing is left in the module
+	entries, err := os.ReadDir("../..")
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), "goconf-check")
+	}
+}
+
+func TestCheckFormatters(t *testing.T) {
+	problems := goconf.CheckErrors{
+		{File: "config.yaml", Line: 3, Column: 1, Field: "port", Message: "Port must be 1,024 or greater"},
+		{File: "config.yaml", Message: "50%\ndone"},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, writeCheckText(&b, problems))
+	assert.Equal(t, "config.yaml:3:1: Port must be 1,024 or greater\nconfig.yaml: 50%\ndone\n", b.String())
+
+	b.Reset()
+	require.NoError(t, writeCheckGitHub(&b, problems))
+	assert.Equal(t, "::error file=config.yaml,line=3,col=1,title=port::Port must be 1,024 or greater\n"+
+		"::error file=config.yaml::50%25%0Adone\n", b.String())
+
+	b.Reset()
+	require.NoError(t, writeCheckJSON(&b, problems[:1]))
+	assert.JSONEq(t, `[{"file":"config.yaml","line":3,"column":1,"field":"port","message":"Port must be 1,024 or greater"}]`, b.String())
+}
+
+func TestGen(t *testing.T) {
+	expected, err := os.ReadFile("testdata/gen/goconf_gen.go")
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run([]string{"gen", "-o", "-", "./testdata/gen"}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, string(expected), stdout.String())
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"gen", "-o", "-", testPackage}, &stdout, &stderr))
+	assert.Equal(t, "goconf gen: no //goconf:config directive found\n", stderr.String())
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"gen", "-o", "-", "./testdata/decoder"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "testdata/decoder/config.go:4:1: field Labels: type map[string]string "+
+		"is not supported by the generated decoder, use decoder=reflect")
+}
+
+func TestGeneratedCode(t *testing.T) {
+	t.Setenv("GEN_NAME", "generated")
+	t.Setenv("GEN_PORT", "9090")
+
+	assert.Equal(t, gen.Config{}, gen.GetConfig())
+
+	require.NoError(t, goconf.Load(new(gen.Config), new(gen.Service)))
+	assert.Equal(t, gen.Config{Name: "generated"}, gen.GetConfig())
+	assert.Equal(t, gen.Service{Port: 9090, Version: "1.2"}, gen.ServiceConfig())
+
+	t.Setenv("GEN_NAME", "")
+	assert.Error(t, goconf.Load(new(gen.Config)))
+}
+
+func TestGeneratedDecoders(t *testing.T) {
+	file, err := os.ReadFile("testdata/gen/generated.yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		yaml   string
+		env    map[string]string
+		naming goconf.EnvNaming
+		keys   goconf.YAMLKeyMatching
+	}{
+		{name: "file", yaml: string(file)},
+		{name: "empty file"},
+		{name: "null document", yaml: "~\n"},
+		{name: "number forms", yaml: "name: app\nport: 0x1F_90\nratio: .5\nretries: 0o7\nburst: -1e3\n"},
+		{name: "yaml bools", yaml: "name: app\ndebug: True\n"},
+		{name: "aliases", yaml: "name: &name app\ntoken: *name\n"},
+		{name: "flow collections", yaml: "name: app\nhosts: [a, b]\nports: [80, 443]\nlimits: {requests: 5}\n"},
+		{name: "empty collections", yaml: "name: app\nhosts: []\nports: ~\ndatabase: {}\n"},
+		{name: "explicit tags", yaml: "name: !!str 123\nport: !!int 80\n"},
+		{name: "unknown key", yaml: "name: app\nprot: 80\n"},
+		{name: "string into int", yaml: "name: app\nport: \"80\"\n"},
+		{name: "int into duration", yaml: "name: app\ntimeout: 5\n"},
+		{name: "scalar into slice", yaml: "name: app\nhosts: a\n"},
+		{name: "uint overflow", yaml: "name: app\nretries: 256\n"},
+		{name: "float32 overflow", yaml: "name: app\nlimits:\n  burst: 1e40\n"},
+		{name: "duplicate key", yaml: "name: a\nname: b\n"},
+		{name: "syntax error", yaml: "name: [\n"},
+		{name: "invalid values", yaml: "name: app\nport: 70000\nmode: test\nlimits:\n  requests: -1\n"},
+		{
+			name: "env overrides",
+			yaml: string(file),
+			env: map[string]string{
+				"GEN_APP_NAME":       "env",
+				"GEN_APP_PORT":       "8081",
+				"GEN_APP_DEBUG":      "true",
+				"GEN_APP_HOSTS":      "a,,b ",
+				"GEN_APP_PORTS":      "1;2",
+				"GEN_APP_TIMEOUT":    "1h30m",
+				"GEN_APP_REGION":     "us-east-1",
+				"GEN_DB_PASSWORD":    "env-secret",
+				"GEN_LIMIT_REQUESTS": "7",
+			},
+		},
+		{name: "empty env", yaml: string(file), env: map[string]string{"GEN_APP_NAME": "", "GEN_APP_REGION": "", "GEN_APP_HOSTS": ""}},
+		{name: "env defaults", env: map[string]string{"GEN_APP_NAME": "env"}},
+		{name: "env base", yaml: string(file), env: map[string]string{"GEN_APP_PORT": "0x10"}},
+		{name: "env bool", yaml: string(file), env: map[string]string{"GEN_APP_DEBUG": "yes"}},
+		{name: "env separator", yaml: string(file), env: map[string]string{"GEN_APP_PORTS": "1; 2"}},
+		{name: "env overflow", yaml: string(file), env: map[string]string{"GEN_APP_RETRIES": "256"}},
+		{
+			name:   "derived env names",
+			yaml:   string(file),
+			env:    map[string]string{"APP_LIMITS_BURST": "2.5", "GEN_APP_PORT": "8081"},
+			naming: goconf.EnvNaming{Derive: true, Prefix: "APP_"},
+		},
+		{
+			name: "loose yaml keys",
+			yaml: "Name: app\nPORT: 9091\nDatabase: {HOST: db}\nlimits: {Requests: 5}\n",
+			keys: goconf.YAMLKeyMatchingLoose,
+		},
+		{name: "env infinity", yaml: string(file), env: map[string]string{"GEN_APP_RATIO": "-Inf"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "testdata", "gen"), 0o700))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "testdata", "gen", "generated.yaml"), []byte(test.yaml), 0o600))
+			t.Chdir(dir)
+
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+
+			goconf.SetEnvNaming(test.naming)
+			defer goconf.SetEnvNaming(goconf.EnvNaming{})
+
+			if test.keys != "" {
+				goconf.SetYAMLKeyMatching(test.keys)
+				defer goconf.SetYAMLKeyMatching(goconf.YAMLKeyMatchingExact)
+			}
+
+			// Load applies the defaults before Register
+			var generated gen.Generated
+			var reflective gen.Reflective
+			require.NoError(t, goconf.ApplyDefaults(&generated))
+			require.NoError(t, goconf.ApplyDefaults(&reflective))
+
+			reflectiveErr := reflective.Register()
+			assert.Equal(t, errorString(reflectiveErr), errorString(generated.Register()))
+			assert.Equal(t, gen.Generated(reflective), generated)
+
+			if reflectiveErr != nil {
+				return
+			}
+
+			validErr := reflective.Validate()
+			assert.Equal(t, errorString(validErr), errorString(generated.Validate()))
+
+			if validErr == nil {
+				assert.Equal(t, printedFields(t, &reflective), printedFields(t, &generated))
+			}
+		})
+	}
+}
+
+func BenchmarkGeneratedDecoders(b *testing.B) {
+	b.Setenv("GEN_APP_NAME", "bench")
+	b.Setenv("GEN_APP_PORTS", "80;443")
+
+	b.Run("register/reflect", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := new(gen.Reflective).Register(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("register/generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := new(gen.Generated).Register(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	var reflective gen.Reflective
+	require.NoError(b, reflective.Register())
+	generated := gen.Generated(reflective)
+
+	b.Run("validate/reflect", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := reflective.Validate(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("validate/generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := generated.Validate(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// errorString returns the message of err with the type names of the compared
+// configs unified, or an empty string if err is nil
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return strings.ReplaceAll(err.Error(), "Reflective", "Generated")
+}
+
+// printedFields loads config and returns the fields handed to the renderer in
+// every key naming and print mode
+func printedFields(t *testing.T, config goconf.Configer) map[string][]goconf.Field {
+	t.Helper()
+
+	const format goconf.OutputFormat = "gen-test"
+
+	var rendered []goconf.Field
+	goconf.RegisterRenderer(format, goconf.RendererFunc(func(_ io.Writer, fields []goconf.Field) error {
+		rendered = fields
+		return nil
+	}))
+	goconf.SetOutputFormat(format)
+
+	t.Cleanup(func() {
+		goconf.SetOutputFormat(goconf.OutputFormatTable)
+		goconf.SetKeyNaming(goconf.KeyNamingField)
+		goconf.SetPrintMode(goconf.PrintModeAll)
+	})
+
+	printed := make(map[string][]goconf.Field)
+
+	for _, naming := range []goconf.KeyNaming{goconf.KeyNamingField, goconf.KeyNamingEnv, goconf.KeyNamingYAML, goconf.KeyNamingJSON} {
+		for _, mode := range []goconf.PrintMode{goconf.PrintModeAll, goconf.PrintModeChanged} {
+			goconf.SetKeyNaming(naming)
+			goconf.SetPrintMode(mode)
+
+			rendered = nil
+			require.NoError(t, goconf.Load(config))
+
+			printed[string(naming)+"/"+string(mode)] = rendered
+		}
+	}
+
+	return printed
+}
+
+func TestParseDirective(t *testing.T) {
+	tests := []struct {
+		name          string
+		typeName      string
+		directive     string
+		expected      genConfig
+		expectedError string
+	}{
+		{
+			name:      "defaults",
+			typeName:  "Config",
+			directive: "//goconf:config",
+			expected:  genConfig{TypeName: "Config", Sources: []string{"env"}, Getter: "GetConfig", Loaded: "loadedConfig"},
+		},
+		{
+			name:      "all options",
+			typeName:  "settings",
+			directive: "//goconf:config source=env,yaml file=config.yaml getter=current",
+			expected: genConfig{
+				TypeName: "settings",
+				Sources:  []string{"env", "yaml"},
+				File:     "config.yaml",
+				Getter:   "current",
+				Loaded:   "loadedSettings",
+			},
+		},
+		{
+			name:      "unexported getter",
+			typeName:  "settings",
+			directive: "//goconf:config",
+			expected:  genConfig{TypeName: "settings", Sources: []string{"env"}, Getter: "getSettings", Loaded: "loadedSettings"},
+		},
+		{
+			name:          "invalid option",
+			directive:     "//goconf:config yaml",
+			expectedError: `invalid option "yaml", options are written as key=value`,
+		},
+		{
+			name:          "unknown option",
+			directive:     "//goconf:config name=x",
+			expectedError: `unknown option "name"`,
+		},
+		{
+			name:          "unknown source",
+			directive:     "//goconf:config source=json",
+			expectedError: `unknown source "json", the sources are env and yaml`,
+		},
+		{
+			name:          "duplicate source",
+			directive:     "//goconf:config source=env,env",
+			expectedError: `source "env" is listed twice`,
+		},
+		{
+			name:          "missing file",
+			directive:     "//goconf:config source=yaml",
+			expectedError: "the file option is required with the yaml source, and only allowed with it",
+		},
+		{
+			name:          "file without yaml",
+			directive:     "//goconf:config file=config.yaml",
+			expectedError: "the file option is required with the yaml source, and only allowed with it",
+		},
+		{
+			name:      "generated decoder",
+			typeName:  "Config",
+			directive: "//goconf:config decoder=generated",
+			expected: genConfig{
+				TypeName:  "Config",
+				Sources:   []string{"env"},
+				Getter:    "GetConfig",
+				Loaded:    "loadedConfig",
+				Generated: true,
+			},
+		},
+		{
+			name:      "reflect decoder",
+			typeName:  "Config",
+			directive: "//goconf:config decoder=reflect",
+			expected:  genConfig{TypeName: "Config", Sources: []string{"env"}, Getter: "GetConfig", Loaded: "loadedConfig"},
+		},
+		{
+			name:          "unknown decoder",
+			directive:     "//goconf:config decoder=fast",
+			expectedError: `unknown decoder "fast", the decoders are generated and reflect`,
+		},
+		{
+			name:          "invalid getter",
+			directive:     "//goconf:config getter=get-config",
+			expectedError: `invalid getter name "get-config"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := parseDirective(test.typeName, test.directive)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, config)
+		})
+	}
+}
+
+func TestK8s(t *testing.T) {
+	expected, err := os.ReadFile("testdata/config.k8s.yaml")
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run([]string{"k8s", "-type", "Config", "-name", "my-app", testPackage}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, string(expected), stdout.String())
+
+	stdout.Reset()
+	require.Equal(t, 0, run([]string{"k8s", "-type", "Config", "-name", "my-app", "-snippet", "envFrom", testPackage}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "envFrom:\n  - configMapRef:\n      name: my-app\n  - secretRef:\n      name: my-app\n", stdout.String())
+
+	assert.Equal(t, 1, run([]string{"k8s", "-type", "Config", testPackage}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "goconf k8s: the -name flag is required")
+}
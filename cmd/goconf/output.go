@@ -0,0 +1,23 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// writeOutput writes the output of write to path, or to stdout if path is empty.
+// The file is only replaced once the whole output has been generated.
+func writeOutput(path string, stdout io.Writer, write func(w io.Writer) error) error {
+	var b bytes.Buffer
+	if err := write(&b); err != nil {
+		return err
+	}
+
+	if path == "" {
+		_, err := stdout.Write(b.Bytes())
+		return err
+	}
+
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
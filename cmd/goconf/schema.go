@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/wgarunap/goconf/spec"
+)
+
+// runSchema writes the JSON Schema of the YAML representation of a config struct
+//
+//	goconf schema -type Config [-o config.schema.json] [packages]
+func runSchema(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("schema", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	typeName := flags.String("type", "", "name of the config struct")
+	output := flags.String("o", "", "output file, standard output if empty")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	fields, err := loadStruct(flags.Args(), *typeName)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, stdout, func(w io.Writer) error {
+		return spec.WriteJSONSchema(w, fields)
+	})
+}
@@ -0,0 +1,29 @@
+// Package config declares a configuration struct used by the goconf command tests
+package config
+
+import (
+	"time"
+
+	"github.com/wgarunap/goconf"
+)
+
+// Config is the configuration of a test service
+type Config struct {
+	Name     string               `env:"APP_NAME" yaml:"name" envDefault:"my app" desc:"Application name" validate:"required"`
+	Port     int                  `env:"PORT" yaml:"port" envDefault:"8080" desc:"HTTP listen port" validate:"gte=1024,lte=65535"`
+	Timeout  time.Duration        `env:"TIMEOUT" yaml:"timeout" default:"30s"`
+	Retries  goconf.Optional[int] `env:"RETRIES" yaml:"retries"`
+	Hosts    []string             `env:"HOSTS" yaml:"hosts"`
+	Legacy   string               `env:"LEGACY" yaml:"legacy" deprecated:"use APP_NAME instead"`
+	Internal string               `yaml:"internal"`
+	Database Database             `yaml:"database" envPrefix:"DB_"`
+	Started  time.Time            `yaml:"started"`
+	Labels   map[string]string    `env:"LABELS" yaml:"labels"`
+	unused   string
+}
+
+// Database is the database configuration
+type Database struct {
+	Host     string `env:"HOST" yaml:"host" envDefault:"localhost" desc:"Database host" validate:"required,hostname"`
+	Password string `env:"PASSWORD" yaml:"password" envDefault:"changeme" secret:"true"`
+}
@@ -0,0 +1,8 @@
+// Package decoder holds a configuration the generated decoders do not support.
+package decoder
+
+//goconf:config decoder=generated
+type Config struct {
+	Name   string            `env:"NAME"`
+	Labels map[string]string `env:"LABELS"`
+}
@@ -0,0 +1,63 @@
+// Package gen declares configuration structs used by the goconf gen tests
+package gen
+
+import "time"
+
+// Config is loaded from the environment
+//
+//goconf:config
+type Config struct {
+	Name     string `env:"GEN_NAME" validate:"required"`
+	Password string `env:"GEN_PASSWORD" secret:"true"`
+}
+
+type (
+	// Service is loaded from a YAML file overridden by the environment
+	//
+	//goconf:config source=yaml,env file=testdata/gen/service.yaml getter=ServiceConfig
+	Service struct {
+		Port    int    `env:"GEN_PORT" yaml:"port" validate:"gte=1024"`
+		Version string `yaml:"version"`
+	}
+
+	// other has no directive
+	other struct{}
+)
+
+// Generated is loaded with the generated decoders
+//
+//goconf:config source=yaml,env file=testdata/gen/generated.yaml decoder=generated
+type Generated struct {
+	Name    string        `env:"GEN_APP_NAME" yaml:"name" json:"name" validate:"required" desc:"Application name"`
+	Port    int           `env:"GEN_APP_PORT" yaml:"port" json:"port" default:"8080" validate:"gte=1,lte=65535"`
+	Debug   bool          `env:"GEN_APP_DEBUG" yaml:"debug"`
+	Ratio   float64       `env:"GEN_APP_RATIO" yaml:"ratio" default:"0.5" validate:"gte=0,lte=1"`
+	Retries uint8         `env:"GEN_APP_RETRIES" yaml:"retries" default:"3" validate:"max=10"`
+	Timeout time.Duration `env:"GEN_APP_TIMEOUT" yaml:"timeout" default:"30s" validate:"gte=1s"`
+	Hosts   []string      `env:"GEN_APP_HOSTS" yaml:"hosts" default:"localhost" validate:"min=1"`
+	Ports   []uint16      `env:"GEN_APP_PORTS" envSeparator:";" yaml:"ports"`
+	Mode    Mode          `env:"GEN_APP_MODE" yaml:"mode" default:"release" validate:"oneof=debug release"`
+	Region  string        `env:"GEN_APP_REGION" envDefault:"eu-west-1" yaml:"-"`
+	Token   string        `env:"GEN_APP_TOKEN" yaml:"token" secret:"true" default:"changeme"`
+
+	Database struct {
+		Host     string `env:"HOST" yaml:"host" default:"db"`
+		Password string `env:"PASSWORD" yaml:"password" secret:"true"`
+	} `envPrefix:"GEN_DB_" yaml:"database"`
+
+	Limits Limits `yaml:"limits" json:"limits"`
+}
+
+// Mode is the run mode of the application
+type Mode string
+
+// Limits is nested in Generated
+type Limits struct {
+	Requests int64   `env:"GEN_LIMIT_REQUESTS" yaml:"requests" validate:"omitempty,gt=0"`
+	Burst    float32 `yaml:"burst" json:"burst"`
+}
+
+// Reflective is loaded with reflection, it has the fields of Generated
+//
+//goconf:config source=yaml,env file=testdata/gen/generated.yaml decoder=reflect
+type Reflective Generated
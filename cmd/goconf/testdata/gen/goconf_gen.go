@@ -0,0 +1,860 @@
+// Code generated by goconf gen. DO NOT EDIT.
+
+package gen
+
+import (
+	"math"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wgarunap/goconf"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ goconf.Configer  = (*Config)(nil)
+	_ goconf.Validater = (*Config)(nil)
+	_ goconf.Printer   = (*Config)(nil)
+)
+
+// loadedConfig is the Config registered with goconf.Load
+var loadedConfig *Config
+
+// Register loads the Config from the environment
+func (c *Config) Register() error {
+	loadedConfig = c
+
+	return goconf.ParseEnv(c)
+}
+
+// Validate validates the Config against its validate tags
+func (c *Config) Validate() error {
+	return goconf.StructValidator(c)
+}
+
+// Print returns the Config to print, secret fields are masked
+func (c *Config) Print() interface{} {
+	return *c
+}
+
+// GetConfig returns a copy of the Config loaded by goconf.Load, or the
+// zero value if it has not been registered
+func GetConfig() Config {
+	if loadedConfig == nil {
+		return Config{}
+	}
+
+	return *loadedConfig
+}
+
+var (
+	_ goconf.Configer  = (*Service)(nil)
+	_ goconf.Validater = (*Service)(nil)
+	_ goconf.Printer   = (*Service)(nil)
+)
+
+// loadedService is the Service registered with goconf.Load
+var loadedService *Service
+
+// Register loads the Service from testdata/gen/service.yaml and then the environment
+func (c *Service) Register() error {
+	loadedService = c
+
+	if err := goconf.ParseYaml(c, "testdata/gen/service.yaml"); err != nil {
+		return err
+	}
+
+	return goconf.ParseEnv(c)
+}
+
+// Validate validates the Service against its validate tags
+func (c *Service) Validate() error {
+	return goconf.StructValidator(c)
+}
+
+// Print returns the Service to print, secret fields are masked
+func (c *Service) Print() interface{} {
+	return *c
+}
+
+// ServiceConfig returns a copy of the Service loaded by goconf.Load, or the
+// zero value if it has not been registered
+func ServiceConfig() Service {
+	if loadedService == nil {
+		return Service{}
+	}
+
+	return *loadedService
+}
+
+var (
+	_ goconf.Configer    = (*Generated)(nil)
+	_ goconf.Validater   = (*Generated)(nil)
+	_ goconf.Printer     = (*Generated)(nil)
+	_ goconf.FieldLister = (*Generated)(nil)
+)
+
+// loadedGenerated is the Generated registered with goconf.Load
+var loadedGenerated *Generated
+
+// Register loads the Generated from testdata/gen/generated.yaml and then the environment.
+// Input the generated decoders do not handle is loaded by goconf.
+func (c *Generated) Register() error {
+	loadedGenerated = c
+
+	if decoded := *c; decoded.decodeYAML("testdata/gen/generated.yaml") {
+		*c = decoded
+	} else if err := goconf.ParseYaml(c, "testdata/gen/generated.yaml"); err != nil {
+		return err
+	}
+
+	if decoded := *c; decoded.decodeEnv() {
+		*c = decoded
+	} else if err := goconf.ParseEnv(c); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates the Generated against its validate tags
+func (c *Generated) Validate() error {
+	if !goconf.DefaultValidator().IsCustomized() && c.valid() {
+		return nil
+	}
+
+	return goconf.StructValidator(c)
+}
+
+// Print returns the Generated to print, secret fields are masked
+func (c *Generated) Print() interface{} {
+	return *c
+}
+
+// GetGenerated returns a copy of the Generated loaded by goconf.Load, or the
+// zero value if it has not been registered
+func GetGenerated() Generated {
+	if loadedGenerated == nil {
+		return Generated{}
+	}
+
+	return *loadedGenerated
+}
+
+// decodeYAML loads the YAML file into c without reflection. It returns false
+// if the file cannot be decoded, goconf.ParseYaml then reports the error.
+func (c *Generated) decodeYAML(file string) bool {
+	if goconf.CurrentYAMLKeyMatching() != goconf.YAMLKeyMatchingExact {
+		return false
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) != 1 {
+		return false
+	}
+
+	if !goconfDecodeMapping(doc.Content[0], func(key string, value *yaml.Node) bool {
+		switch key {
+		case "name":
+			if !goconfIsScalar(value, "!!str") {
+				return false
+			}
+			c.Name = value.Value
+		case "port":
+			if !goconfIsInt(value) {
+				return false
+			}
+			parsed, err := strconv.ParseInt(value.Value, 10, strconv.IntSize)
+			if err != nil {
+				return false
+			}
+			c.Port = int(parsed)
+		case "debug":
+			if !goconfIsScalar(value, "!!bool") {
+				return false
+			}
+			parsed, err := strconv.ParseBool(value.Value)
+			if err != nil {
+				return false
+			}
+			c.Debug = parsed
+		case "ratio":
+			if !goconfIsFloat(value) {
+				return false
+			}
+			parsed, err := strconv.ParseFloat(value.Value, 64)
+			if err != nil {
+				return false
+			}
+			c.Ratio = parsed
+		case "retries":
+			if !goconfIsInt(value) {
+				return false
+			}
+			parsed, err := strconv.ParseUint(value.Value, 10, 8)
+			if err != nil {
+				return false
+			}
+			c.Retries = uint8(parsed)
+		case "timeout":
+			if !goconfIsScalar(value, "!!str") {
+				return false
+			}
+			parsed, err := time.ParseDuration(value.Value)
+			if err != nil {
+				return false
+			}
+			c.Timeout = parsed
+		case "hosts":
+			if !goconfIsSequence(value) {
+				return false
+			}
+			items := make([]string, len(value.Content))
+			for i, item := range value.Content {
+				if !goconfIsScalar(item, "!!str") {
+					return false
+				}
+				items[i] = item.Value
+			}
+			c.Hosts = items
+		case "ports":
+			if !goconfIsSequence(value) {
+				return false
+			}
+			items := make([]uint16, len(value.Content))
+			for i, item := range value.Content {
+				if !goconfIsInt(item) {
+					return false
+				}
+				parsed, err := strconv.ParseUint(item.Value, 10, 16)
+				if err != nil {
+					return false
+				}
+				items[i] = uint16(parsed)
+			}
+			c.Ports = items
+		case "mode":
+			if !goconfIsScalar(value, "!!str") {
+				return false
+			}
+			c.Mode = Mode(value.Value)
+		case "token":
+			if !goconfIsScalar(value, "!!str") {
+				return false
+			}
+			c.Token = value.Value
+		case "database":
+			return goconfDecodeMapping(value, func(key string, value *yaml.Node) bool {
+				switch key {
+				case "host":
+					if !goconfIsScalar(value, "!!str") {
+						return false
+					}
+					c.Database.Host = value.Value
+				case "password":
+					if !goconfIsScalar(value, "!!str") {
+						return false
+					}
+					c.Database.Password = value.Value
+				}
+
+				return true
+			})
+		case "limits":
+			return goconfDecodeMapping(value, func(key string, value *yaml.Node) bool {
+				switch key {
+				case "requests":
+					if !goconfIsInt(value) {
+						return false
+					}
+					parsed, err := strconv.ParseInt(value.Value, 10, 64)
+					if err != nil {
+						return false
+					}
+					c.Limits.Requests = parsed
+				case "burst":
+					if !goconfIsFloat(value) {
+						return false
+					}
+					parsed, err := strconv.ParseFloat(value.Value, 64)
+					if err != nil || math.Abs(parsed) > math.MaxFloat32 {
+						return false
+					}
+					c.Limits.Burst = float32(parsed)
+				}
+
+				return true
+			})
+		}
+
+		return true
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// decodeEnv loads the environment into c without reflection. It returns false
+// if a value cannot be decoded, goconf.ParseEnv then reports the error.
+func (c *Generated) decodeEnv() bool {
+	if goconf.CurrentEnvNaming().Derive {
+		return false
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_NAME"); ok && value != "" {
+		c.Name = value
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_PORT"); ok && value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return false
+		}
+		c.Port = int(parsed)
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_DEBUG"); ok && value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return false
+		}
+		c.Debug = parsed
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_RATIO"); ok && value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		c.Ratio = parsed
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_RETRIES"); ok && value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return false
+		}
+		c.Retries = uint8(parsed)
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_TIMEOUT"); ok && value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return false
+		}
+		c.Timeout = parsed
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_HOSTS"); ok && value != "" {
+		parts := strings.Split(value, ",")
+		items := make([]string, len(parts))
+		for i, part := range parts {
+			items[i] = part
+		}
+		c.Hosts = items
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_PORTS"); ok && value != "" {
+		parts := strings.Split(value, ";")
+		items := make([]uint16, len(parts))
+		for i, part := range parts {
+			parsed, err := strconv.ParseUint(part, 10, 16)
+			if err != nil {
+				return false
+			}
+			items[i] = uint16(parsed)
+		}
+		c.Ports = items
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_MODE"); ok && value != "" {
+		c.Mode = Mode(value)
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_REGION"); ok && value != "" {
+		c.Region = value
+	} else {
+		c.Region = "eu-west-1"
+	}
+
+	if value, ok := os.LookupEnv("GEN_APP_TOKEN"); ok && value != "" {
+		c.Token = value
+	}
+
+	if value, ok := os.LookupEnv("GEN_DB_HOST"); ok && value != "" {
+		c.Database.Host = value
+	}
+
+	if value, ok := os.LookupEnv("GEN_DB_PASSWORD"); ok && value != "" {
+		c.Database.Password = value
+	}
+
+	if value, ok := os.LookupEnv("GEN_LIMIT_REQUESTS"); ok && value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		c.Limits.Requests = parsed
+	}
+
+	return true
+}
+
+// valid reports whether c satisfies its validate tags, goconf.StructValidator
+// reports the errors otherwise
+func (c *Generated) valid() bool {
+	if c.Name == "" {
+		return false
+	}
+
+	if int64(c.Port) < 1 {
+		return false
+	}
+	if int64(c.Port) > 65535 {
+		return false
+	}
+
+	if !(float64(c.Ratio) >= 0) {
+		return false
+	}
+	if !(float64(c.Ratio) <= 1) {
+		return false
+	}
+
+	if uint64(c.Retries) > 10 {
+		return false
+	}
+
+	if int64(c.Timeout) < 1000000000 {
+		return false
+	}
+
+	if int64(len(c.Hosts)) < 1 {
+		return false
+	}
+
+	if c.Mode != "debug" && c.Mode != "release" {
+		return false
+	}
+
+	if c.Limits.Requests != 0 {
+		if int64(c.Limits.Requests) <= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ListFields lists the fields of the Generated for printing without reflection
+func (c *Generated) ListFields(naming goconf.KeyNaming) []goconf.Field {
+	fields := make([]goconf.Field, 0, 13)
+
+	{
+		f0 := goconf.Field{
+			Path:        []string{"Name"},
+			Value:       c.Name,
+			Type:        loadedGeneratedTypes[0],
+			Source:      "GEN_APP_NAME",
+			Tags:        `env:"GEN_APP_NAME" yaml:"name" json:"name" validate:"required" desc:"Application name"`,
+			Description: "Application name",
+			IsDefault:   c.Name == "",
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Name", "name", "name", "GEN_APP_NAME")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:       []string{"Port"},
+			Value:      c.Port,
+			Type:       loadedGeneratedTypes[1],
+			Source:     "GEN_APP_PORT",
+			Tags:       `env:"GEN_APP_PORT" yaml:"port" json:"port" default:"8080" validate:"gte=1,lte=65535"`,
+			Default:    "8080",
+			HasDefault: true,
+			IsDefault:  c.Port == 8080,
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Port", "port", "port", "GEN_APP_PORT")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:      []string{"Debug"},
+			Value:     c.Debug,
+			Type:      loadedGeneratedTypes[2],
+			Source:    "GEN_APP_DEBUG",
+			Tags:      `env:"GEN_APP_DEBUG" yaml:"debug"`,
+			IsDefault: !c.Debug,
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Debug", "debug", "", "GEN_APP_DEBUG")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:       []string{"Ratio"},
+			Value:      c.Ratio,
+			Type:       loadedGeneratedTypes[3],
+			Source:     "GEN_APP_RATIO",
+			Tags:       `env:"GEN_APP_RATIO" yaml:"ratio" default:"0.5" validate:"gte=0,lte=1"`,
+			Default:    "0.5",
+			HasDefault: true,
+			IsDefault:  c.Ratio == 0.5,
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Ratio", "ratio", "", "GEN_APP_RATIO")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:       []string{"Retries"},
+			Value:      c.Retries,
+			Type:       loadedGeneratedTypes[4],
+			Source:     "GEN_APP_RETRIES",
+			Tags:       `env:"GEN_APP_RETRIES" yaml:"retries" default:"3" validate:"max=10"`,
+			Default:    "3",
+			HasDefault: true,
+			IsDefault:  c.Retries == 3,
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Retries", "retries", "", "GEN_APP_RETRIES")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:       []string{"Timeout"},
+			Value:      c.Timeout,
+			Type:       loadedGeneratedTypes[5],
+			Source:     "GEN_APP_TIMEOUT",
+			Tags:       `env:"GEN_APP_TIMEOUT" yaml:"timeout" default:"30s" validate:"gte=1s"`,
+			Default:    "30s",
+			HasDefault: true,
+			IsDefault:  c.Timeout == 30*time.Second,
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Timeout", "timeout", "", "GEN_APP_TIMEOUT")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:       []string{"Hosts"},
+			Value:      c.Hosts,
+			Type:       loadedGeneratedTypes[6],
+			Source:     "GEN_APP_HOSTS",
+			Tags:       `env:"GEN_APP_HOSTS" yaml:"hosts" default:"localhost" validate:"min=1"`,
+			Default:    "localhost",
+			HasDefault: true,
+			IsDefault:  slices.Equal(c.Hosts, []string{"localhost"}),
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Hosts", "hosts", "", "GEN_APP_HOSTS")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:      []string{"Ports"},
+			Value:     c.Ports,
+			Type:      loadedGeneratedTypes[7],
+			Source:    "GEN_APP_PORTS",
+			Tags:      `env:"GEN_APP_PORTS" envSeparator:";" yaml:"ports"`,
+			IsDefault: c.Ports == nil,
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Ports", "ports", "", "GEN_APP_PORTS")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:       []string{"Mode"},
+			Value:      c.Mode,
+			Type:       loadedGeneratedTypes[8],
+			Source:     "GEN_APP_MODE",
+			Tags:       `env:"GEN_APP_MODE" yaml:"mode" default:"release" validate:"oneof=debug release"`,
+			Default:    "release",
+			HasDefault: true,
+			IsDefault:  c.Mode == "release",
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Mode", "mode", "", "GEN_APP_MODE")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:       []string{"Region"},
+			Value:      c.Region,
+			Type:       loadedGeneratedTypes[9],
+			Source:     "GEN_APP_REGION",
+			Tags:       `env:"GEN_APP_REGION" envDefault:"eu-west-1" yaml:"-"`,
+			Default:    "eu-west-1",
+			HasDefault: true,
+			IsDefault:  c.Region == "eu-west-1",
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Region", "", "", "GEN_APP_REGION")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:       []string{"Token"},
+			Value:      c.Token,
+			Type:       loadedGeneratedTypes[10],
+			Source:     "GEN_APP_TOKEN",
+			Tags:       `env:"GEN_APP_TOKEN" yaml:"token" secret:"true" default:"changeme"`,
+			Secret:     true,
+			Default:    "changeme",
+			HasDefault: true,
+			IsDefault:  c.Token == "changeme",
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Token", "token", "", "GEN_APP_TOKEN")
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:      []string{"Database"},
+			Type:      loadedGeneratedTypes[11],
+			Tags:      `envPrefix:"GEN_DB_" yaml:"database"`,
+			IsDefault: c.Database.Host == "" && c.Database.Password == "",
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Database", "database", "", "")
+		f0.Fields = make([]goconf.Field, 0, 2)
+
+		{
+			f1 := goconf.Field{
+				Path:       []string{"Database", "Host"},
+				Value:      c.Database.Host,
+				Type:       loadedGeneratedTypes[12],
+				Source:     "GEN_DB_HOST",
+				Tags:       `env:"HOST" yaml:"host" default:"db"`,
+				Default:    "db",
+				HasDefault: true,
+				IsDefault:  c.Database.Host == "db",
+			}
+			f1.Name, f1.Key = goconfKey(naming, f0.Key, "Host", "host", "", "GEN_DB_HOST")
+
+			f0.Fields = append(f0.Fields, f1)
+		}
+
+		{
+			f1 := goconf.Field{
+				Path:      []string{"Database", "Password"},
+				Value:     c.Database.Password,
+				Type:      loadedGeneratedTypes[13],
+				Source:    "GEN_DB_PASSWORD",
+				Tags:      `env:"PASSWORD" yaml:"password" secret:"true"`,
+				Secret:    true,
+				IsDefault: c.Database.Password == "",
+			}
+			f1.Name, f1.Key = goconfKey(naming, f0.Key, "Password", "password", "", "GEN_DB_PASSWORD")
+
+			f0.Fields = append(f0.Fields, f1)
+		}
+
+		fields = append(fields, f0)
+	}
+
+	{
+		f0 := goconf.Field{
+			Path:      []string{"Limits"},
+			Type:      loadedGeneratedTypes[14],
+			Tags:      `yaml:"limits" json:"limits"`,
+			IsDefault: c.Limits.Requests == 0 && math.Float64bits(float64(c.Limits.Burst)) == 0,
+		}
+		f0.Name, f0.Key = goconfKey(naming, "", "Limits", "limits", "limits", "")
+		f0.Fields = make([]goconf.Field, 0, 2)
+
+		{
+			f1 := goconf.Field{
+				Path:      []string{"Limits", "Requests"},
+				Value:     c.Limits.Requests,
+				Type:      loadedGeneratedTypes[15],
+				Source:    "GEN_LIMIT_REQUESTS",
+				Tags:      `env:"GEN_LIMIT_REQUESTS" yaml:"requests" validate:"omitempty,gt=0"`,
+				IsDefault: c.Limits.Requests == 0,
+			}
+			f1.Name, f1.Key = goconfKey(naming, f0.Key, "Requests", "requests", "", "GEN_LIMIT_REQUESTS")
+
+			f0.Fields = append(f0.Fields, f1)
+		}
+
+		{
+			f1 := goconf.Field{
+				Path:      []string{"Limits", "Burst"},
+				Value:     c.Limits.Burst,
+				Type:      loadedGeneratedTypes[16],
+				Tags:      `yaml:"burst" json:"burst"`,
+				IsDefault: math.Float64bits(float64(c.Limits.Burst)) == 0,
+			}
+			f1.Name, f1.Key = goconfKey(naming, f0.Key, "Burst", "burst", "burst", "")
+
+			f0.Fields = append(f0.Fields, f1)
+		}
+
+		fields = append(fields, f0)
+	}
+
+	return fields
+}
+
+// loadedGeneratedTypes holds the types of the fields listed by ListFields
+var loadedGeneratedTypes = [...]reflect.Type{
+	reflect.TypeOf(Generated{}.Name),
+	reflect.TypeOf(Generated{}.Port),
+	reflect.TypeOf(Generated{}.Debug),
+	reflect.TypeOf(Generated{}.Ratio),
+	reflect.TypeOf(Generated{}.Retries),
+	reflect.TypeOf(Generated{}.Timeout),
+	reflect.TypeOf(Generated{}.Hosts),
+	reflect.TypeOf(Generated{}.Ports),
+	reflect.TypeOf(Generated{}.Mode),
+	reflect.TypeOf(Generated{}.Region),
+	reflect.TypeOf(Generated{}.Token),
+	reflect.TypeOf(Generated{}.Database),
+	reflect.TypeOf(Generated{}.Database.Host),
+	reflect.TypeOf(Generated{}.Database.Password),
+	reflect.TypeOf(Generated{}.Limits),
+	reflect.TypeOf(Generated{}.Limits.Requests),
+	reflect.TypeOf(Generated{}.Limits.Burst),
+}
+
+var (
+	_ goconf.Configer  = (*Reflective)(nil)
+	_ goconf.Validater = (*Reflective)(nil)
+	_ goconf.Printer   = (*Reflective)(nil)
+)
+
+// loadedReflective is the Reflective registered with goconf.Load
+var loadedReflective *Reflective
+
+// Register loads the Reflective from testdata/gen/generated.yaml and then the environment
+func (c *Reflective) Register() error {
+	loadedReflective = c
+
+	if err := goconf.ParseYaml(c, "testdata/gen/generated.yaml"); err != nil {
+		return err
+	}
+
+	return goconf.ParseEnv(c)
+}
+
+// Validate validates the Reflective against its validate tags
+func (c *Reflective) Validate() error {
+	return goconf.StructValidator(c)
+}
+
+// Print returns the Reflective to print, secret fields are masked
+func (c *Reflective) Print() interface{} {
+	return *c
+}
+
+// GetReflective returns a copy of the Reflective loaded by goconf.Load, or the
+// zero value if it has not been registered
+func GetReflective() Reflective {
+	if loadedReflective == nil {
+		return Reflective{}
+	}
+
+	return *loadedReflective
+}
+
+// goconfKey returns the name and key of a field in a key naming mode
+func goconfKey(naming goconf.KeyNaming, prefix, field, yamlName, jsonName, env string) (string, string) {
+	name := field
+
+	switch {
+	case naming == goconf.KeyNamingEnv && env != "":
+		return env, env
+	case naming == goconf.KeyNamingYAML && yamlName != "":
+		name = yamlName
+	case naming == goconf.KeyNamingJSON && jsonName != "":
+		name = jsonName
+	}
+
+	if prefix == "" {
+		return name, name
+	}
+
+	return name, prefix + "." + name
+}
+
+// goconfDecodeMapping calls decode for the values of a mapping with plain and
+// unique string keys and reports whether all of them were decoded
+func goconfDecodeMapping(node *yaml.Node, decode func(key string, value *yaml.Node) bool) bool {
+	if node.Kind != yaml.MappingNode || node.ShortTag() != "!!map" {
+		return false
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !goconfIsScalar(key, "!!str") {
+			return false
+		}
+
+		for j := 0; j < i; j += 2 {
+			if node.Content[j].Value == key.Value {
+				return false
+			}
+		}
+
+		if !decode(key.Value, node.Content[i+1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// goconfIsSequence reports whether node is a sequence without an explicit tag
+func goconfIsSequence(node *yaml.Node) bool {
+	return node.Kind == yaml.SequenceNode && node.ShortTag() == "!!seq"
+}
+
+// goconfIsScalar reports whether node is a scalar with the given tag resolved
+// from its value rather than set explicitly
+func goconfIsScalar(node *yaml.Node, tag string) bool {
+	return node.Kind == yaml.ScalarNode && node.Style&yaml.TaggedStyle == 0 && node.ShortTag() == tag
+}
+
+// goconfIsInt reports whether node is a decimal integer without leading zeros,
+// which yaml.v3 reads like strconv.ParseInt with base 10
+func goconfIsInt(node *yaml.Node) bool {
+	if !goconfIsScalar(node, "!!int") {
+		return false
+	}
+
+	digits := strings.TrimLeft(node.Value, "+-")
+	if digits == "" || len(node.Value)-len(digits) > 1 || (len(digits) > 1 && digits[0] == '0') {
+		return false
+	}
+
+	return strings.Trim(digits, "0123456789") == ""
+}
+
+// goconfIsFloat reports whether node is a decimal number, which yaml.v3 reads
+// like strconv.ParseFloat
+func goconfIsFloat(node *yaml.Node) bool {
+	return goconfIsInt(node) || goconfIsScalar(node, "!!float") && !strings.Contains(node.Value, "_")
+}
@@ -0,0 +1,143 @@
+package goconf
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	locationType        = reflect.TypeOf(time.Location{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setFromString parses raw into value using the same rules as the env parser:
+// slices are comma separated and maps are comma separated key:value pairs.
+func setFromString(value reflect.Value, raw string) error {
+	if value.CanAddr() && value.Addr().Type().Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	if value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.Type().Elem() == locationType {
+			loc, err := time.LoadLocation(raw)
+			if err != nil {
+				return err
+			}
+
+			value.Set(reflect.ValueOf(loc))
+
+			return nil
+		}
+
+		elem := reflect.New(value.Type().Elem())
+		if err := setFromString(elem.Elem(), raw); err != nil {
+			return err
+		}
+
+		value.Set(elem)
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 0, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 0, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetFloat(f)
+	case reflect.Slice:
+		if raw == "" {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		}
+
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setFromString(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+
+		value.Set(slice)
+	case reflect.Map:
+		m := reflect.MakeMap(value.Type())
+
+		for _, pair := range strings.Split(raw, ",") {
+			if pair == "" {
+				continue
+			}
+
+			k, v, ok := strings.Cut(pair, ":")
+			if !ok {
+				return fmt.Errorf("invalid map item %q, expected key:value", pair)
+			}
+
+			key := reflect.New(value.Type().Key()).Elem()
+			if err := setFromString(key, strings.TrimSpace(k)); err != nil {
+				return err
+			}
+
+			elem := reflect.New(value.Type().Elem()).Elem()
+			if err := setFromString(elem, strings.TrimSpace(v)); err != nil {
+				return err
+			}
+
+			m.SetMapIndex(key, elem)
+		}
+
+		value.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+
+	return nil
+}
+
+// isDefaultValue reports whether value equals the default given as a string.
+// If the default cannot be parsed into the value type the raw strings are compared.
+func isDefaultValue(value reflect.Value, raw string) bool {
+	parsed := reflect.New(value.Type()).Elem()
+	if err := setFromString(parsed, raw); err != nil {
+		return formatValue(value) == raw
+	}
+
+	return reflect.DeepEqual(value.Interface(), parsed.Interface())
+}
@@ -0,0 +1,68 @@
+package goconf
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetFromString(t *testing.T) {
+	tests := map[string]struct {
+		raw         string
+		expected    interface{}
+		expectedErr string
+	}{
+		"string":         {raw: "hello", expected: "hello"},
+		"bool":           {raw: "true", expected: true},
+		"int":            {raw: "-42", expected: -42},
+		"int8 overflow":  {raw: "300", expected: int8(0), expectedErr: "out of range"},
+		"uint":           {raw: "42", expected: uint(42)},
+		"float":          {raw: "1.5", expected: 1.5},
+		"duration":       {raw: "1m30s", expected: 90 * time.Second},
+		"slice":          {raw: "a, b,c", expected: []string{"a", "b", "c"}},
+		"int slice":      {raw: "1,2", expected: []int{1, 2}},
+		"empty slice":    {raw: "", expected: []string(nil)},
+		"map":            {raw: "a:1,b:2", expected: map[string]int{"a": 1, "b": 2}},
+		"invalid map":    {raw: "a", expected: map[string]int{}, expectedErr: "expected key:value"},
+		"pointer":        {raw: "7", expected: intPtr(7)},
+		"text unmarshal": {raw: "10.0.0.1", expected: net.ParseIP("10.0.0.1")},
+		"invalid int":    {raw: "abc", expected: 0, expectedErr: "invalid syntax"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			value := reflect.New(reflect.TypeOf(test.expected)).Elem()
+
+			err := setFromString(value, test.raw)
+			if test.expectedErr != "" {
+				require.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, value.Interface())
+		})
+	}
+}
+
+func TestSetFromStringLocation(t *testing.T) {
+	var loc *time.Location
+
+	require.NoError(t, setFromString(reflect.ValueOf(&loc).Elem(), "UTC"))
+	assert.Equal(t, time.UTC, loc)
+}
+
+func TestIsDefaultValue(t *testing.T) {
+	assert.True(t, isDefaultValue(reflect.ValueOf(5*time.Second), "5s"))
+	assert.False(t, isDefaultValue(reflect.ValueOf(6*time.Second), "5s"))
+	assert.True(t, isDefaultValue(reflect.ValueOf([]string{"a", "b"}), "a,b"))
+	assert.True(t, isDefaultValue(reflect.ValueOf(struct{ A int }{}), "{0}"), "unparsable defaults compare as strings")
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,163 @@
+package goconf
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultTag holds a source independent default value of a field, parsed with
+// the same rules as the env parser: slices are comma separated and maps are
+// comma separated key:value pairs.
+const defaultTag = "default"
+
+// ApplyDefaults sets every zero valued field of config to the value of its
+// `default` tag, including the fields of nested structs and of non-nil pointers to
+// structs. Nil pointers to structs are left nil. Fields holding a non-zero value,
+// e.g. set by a Defaulter, are left untouched.
+//
+// Load calls ApplyDefaults once before Register, so every source read by Register
+// overrides the defaults, and a value a source sets to zero is not replaced by the
+// default when a later source does not set it. ParseEnv and ParseYaml do not apply
+// defaults, call ApplyDefaults before them when they are used without Load. The
+// env parser still sets the `envDefault` tag of the fields whose variable is not
+// set, overriding the values of earlier sources.
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    Timeout time.Duration     `json:"timeout" default:"30s"`
+//	    Hosts   []string          `json:"hosts" default:"localhost"`
+//	    Labels  map[string]string `json:"labels" default:"team:core,tier:web"`
+//	}
+//
+//	var cfg Config
+//	if err := goconf.ApplyDefaults(&cfg); err != nil {
+//	    // Handle invalid default
+//	}
+func ApplyDefaults(config interface{}) error {
+	values := reflect.ValueOf(config)
+	if values.Kind() != reflect.Ptr || values.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	return applyNestedDefaults(values.Elem(), nil)
+}
+
+// sourcePresence reports whether a configuration source sets a field, e.g. to copy
+// a deprecated field into its replacement only when set
+type sourcePresence interface {
+	has(field reflect.StructField) bool
+	nested(field reflect.StructField) sourcePresence
+}
+
+// envSource reports the fields whose environment variable is set
+type envSource struct {
+	path EnvPath
+}
+
+func (s envSource) has(field reflect.StructField) bool {
+	key, ok := s.path.Key(field)
+	if !ok {
+		return false
+	}
+
+	_, ok = os.LookupEnv(key)
+
+	return ok
+}
+
+func (s envSource) nested(field reflect.StructField) sourcePresence {
+	return envSource{path: s.path.Nested(field)}
+}
+
+// yamlSource reports the fields whose key is present in a YAML mapping
+type yamlSource struct {
+	node *yaml.Node
+}
+
+func (s yamlSource) has(field reflect.StructField) bool {
+	return s.value(field) != nil
+}
+
+func (s yamlSource) nested(field reflect.StructField) sourcePresence {
+	if _, flags, _ := strings.Cut(field.Tag.Get("yaml"), ","); strings.Contains(flags, "inline") {
+		return s
+	}
+
+	return yamlSource{node: s.value(field)}
+}
+
+// value returns the value node of the field's key, or nil if the key is absent
+func (s yamlSource) value(field reflect.StructField) *yaml.Node {
+	if s.node == nil || s.node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	key := tagName(field.Tag, "yaml")
+	if key == "" {
+		key = strings.ToLower(field.Name)
+	}
+
+	for i := 0; i+1 < len(s.node.Content); i += 2 {
+		if s.node.Content[i].Value == key {
+			return s.node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// newYAMLSource returns the presence of the top level mapping of a YAML document
+func newYAMLSource(data []byte) (sourcePresence, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return yamlSource{node: doc.Content[0]}, nil
+	}
+
+	return yamlSource{}, nil
+}
+
+func applyNestedDefaults(values reflect.Value, path []string) error {
+	for i := 0; i < values.NumField(); i++ {
+		field := values.Field(i)
+		structField := values.Type().Field(i)
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], structField.Name)
+
+		raw, ok := structField.Tag.Lookup(defaultTag)
+		if !ok {
+			// Nested structs behind a nil pointer are left nil, allocating them
+			// would make an absent section look configured
+			nested := reflect.Indirect(field)
+			if nested.Kind() == reflect.Struct && !reflect.PointerTo(nested.Type()).Implements(textUnmarshalerType) {
+				if err := applyNestedDefaults(nested, fieldPath); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if !field.IsZero() {
+			continue
+		}
+
+		if err := setFromString(field, raw); err != nil {
+			return fmt.Errorf("invalid default %q of field %s: %w", raw, strings.Join(fieldPath, "."), err)
+		}
+	}
+
+	return nil
+}
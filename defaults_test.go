@@ -0,0 +1,246 @@
+package goconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type defaultsConfig struct {
+	Name     string            `yaml:"name" env:"DEFAULTS_NAME" default:"app"`
+	Port     int               `yaml:"port" env:"DEFAULTS_PORT" default:"8080"`
+	Debug    bool              `yaml:"debug" env:"DEFAULTS_DEBUG" default:"true"`
+	Timeout  time.Duration     `yaml:"timeout" env:"DEFAULTS_TIMEOUT" default:"30s"`
+	Hosts    []string          `yaml:"hosts" env:"DEFAULTS_HOSTS" default:"a,b"`
+	Labels   map[string]string `yaml:"labels" env:"DEFAULTS_LABELS" default:"team:core"`
+	Database struct {
+		Host string `yaml:"host" env:"HOST" default:"localhost"`
+	} `yaml:"database" envPrefix:"DEFAULTS_DB_"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	var cfg defaultsConfig
+	cfg.Port = 9090
+
+	require.NoError(t, ApplyDefaults(&cfg))
+
+	assert.Equal(t, "app", cfg.Name)
+	assert.Equal(t, 9090, cfg.Port)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 30*time.Second, cfg.Timeout)
+	assert.Equal(t, []string{"a", "b"}, cfg.Hosts)
+	assert.Equal(t, map[string]string{"team": "core"}, cfg.Labels)
+	assert.Equal(t, "localhost", cfg.Database.Host)
+
+	assert.NoError(t, ApplyDefaults(cfg), "non pointer configs are ignored")
+
+	invalid := struct {
+		Nested struct {
+			Port int `default:"eighty"`
+		}
+	}{}
+	err := ApplyDefaults(&invalid)
+	assert.ErrorContains(t, err, `invalid default "eighty" of field Nested.Port`)
+}
+
+func TestParseYamlDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		yamlContent string
+		expected    func(*defaultsConfig)
+	}{
+		{
+			name:        "absent keys keep the default",
+			yamlContent: "name: svc\nlabels: {tier: web}\n",
+			expected: func(c *defaultsConfig) {
+				*c = defaultsConfig{Name: "svc", Port: 8080, Debug: true, Timeout: 30 * time.Second,
+					Hosts: []string{"a", "b"}, Labels: map[string]string{"tier": "web"}}
+				c.Database.Host = "localhost"
+			},
+		},
+		{
+			name:        "explicit zero values override the default",
+			yamlContent: "name: ''\nport: 0\ndebug: false\ntimeout: 0s\nhosts: []\nlabels: {}\ndatabase:\n  host: ''\n",
+			expected: func(c *defaultsConfig) {
+				*c = defaultsConfig{Hosts: []string{}, Labels: map[string]string{}}
+			},
+		},
+		{
+			name:        "empty file keeps all defaults",
+			yamlContent: "",
+			expected: func(c *defaultsConfig) {
+				*c = defaultsConfig{Name: "app", Port: 8080, Debug: true, Timeout: 30 * time.Second,
+					Hosts: []string{"a", "b"}, Labels: map[string]string{"team": "core"}}
+				c.Database.Host = "localhost"
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(filePath, []byte(test.yamlContent), 0o600))
+
+			var expected, cfg defaultsConfig
+			test.expected(&expected)
+
+			require.NoError(t, ApplyDefaults(&cfg))
+			require.NoError(t, ParseYaml(&cfg, filePath))
+			assert.Equal(t, expected, cfg)
+		})
+	}
+
+	t.Run("no defaults without ApplyDefaults", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(filePath, []byte("name: svc\n"), 0o600))
+
+		var cfg defaultsConfig
+		require.NoError(t, ParseYaml(&cfg, filePath))
+		assert.Equal(t, defaultsConfig{Name: "svc"}, cfg)
+	})
+}
+
+func TestParseEnvDefaults(t *testing.T) {
+	t.Setenv("DEFAULTS_NAME", "svc")
+	t.Setenv("DEFAULTS_PORT", "0")
+	t.Setenv("DEFAULTS_DB_HOST", "db")
+
+	var cfg defaultsConfig
+	require.NoError(t, ApplyDefaults(&cfg))
+	require.NoError(t, ParseEnv(&cfg))
+
+	assert.Equal(t, "svc", cfg.Name)
+	assert.Equal(t, 0, cfg.Port, "explicitly set to zero")
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 30*time.Second, cfg.Timeout)
+	assert.Equal(t, []string{"a", "b"}, cfg.Hosts)
+	assert.Equal(t, "db", cfg.Database.Host)
+}
+
+// sourcesConfig loads defaultsConfig from its sources in order, like the
+// Register method generated by goconf gen
+type sourcesConfig struct {
+	Config  defaultsConfig
+	sources []func(*defaultsConfig) error
+}
+
+func (c *sourcesConfig) Register() error {
+	for _, source := range c.sources {
+		if err := source(&c.Config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestLoadDefaults(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	parseYaml := func(c *defaultsConfig) error { return ParseYaml(c, filePath) }
+	parseEnv := func(c *defaultsConfig) error { return ParseEnv(c) }
+
+	t.Run("yaml then env keeps the zeros of the file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filePath, []byte("port: 0\ndebug: false\nlabels: {}\n"), 0o600))
+		t.Setenv("DEFAULTS_NAME", "svc")
+
+		cfg := &sourcesConfig{sources: []func(*defaultsConfig) error{parseYaml, parseEnv}}
+		require.NoError(t, Load(cfg))
+
+		assert.Equal(t, "svc", cfg.Config.Name)
+		assert.Equal(t, 0, cfg.Config.Port)
+		assert.False(t, cfg.Config.Debug)
+		assert.Equal(t, map[string]string{}, cfg.Config.Labels)
+		assert.Equal(t, 30*time.Second, cfg.Config.Timeout, "set by neither source")
+	})
+
+	t.Run("env then yaml keeps the zeros of the environment", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filePath, []byte("{}\n"), 0o600))
+		t.Setenv("DEFAULTS_PORT", "0")
+		t.Setenv("DEFAULTS_DEBUG", "false")
+
+		cfg := &sourcesConfig{sources: []func(*defaultsConfig) error{parseEnv, parseYaml}}
+		require.NoError(t, Load(cfg))
+
+		assert.Equal(t, 0, cfg.Config.Port)
+		assert.False(t, cfg.Config.Debug)
+		assert.Equal(t, "app", cfg.Config.Name, "set by neither source")
+	})
+
+	t.Run("later sources override earlier ones", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filePath, []byte("port: 9090\n"), 0o600))
+		t.Setenv("DEFAULTS_PORT", "0")
+
+		cfg := &sourcesConfig{sources: []func(*defaultsConfig) error{parseYaml, parseEnv}}
+		require.NoError(t, Load(cfg))
+		assert.Equal(t, 0, cfg.Config.Port)
+	})
+}
+
+type precedenceConfig struct {
+	Both    string             `yaml:"both" env:"PRECEDENCE_BOTH" envDefault:"env default" default:"default"`
+	Preset  string             `yaml:"preset" env:"PRECEDENCE_PRESET" default:"default"`
+	Pointer *precedenceSection `yaml:"pointer" envPrefix:"PRECEDENCE_POINTER_"`
+	Nil     *precedenceSection `yaml:"nil" envPrefix:"PRECEDENCE_NIL_"`
+}
+
+type precedenceSection struct {
+	Host string `yaml:"host" env:"HOST" default:"localhost"`
+	Port int    `yaml:"port" env:"PORT" default:"8080"`
+}
+
+func TestDefaultsPrecedence(t *testing.T) {
+	newConfig := func(t *testing.T) *precedenceConfig {
+		cfg := &precedenceConfig{Preset: "preset", Pointer: &precedenceSection{}}
+		require.NoError(t, ApplyDefaults(cfg))
+
+		return cfg
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg := newConfig(t)
+
+		assert.Equal(t, "default", cfg.Both)
+		assert.Equal(t, "preset", cfg.Preset, "a value already set wins over default")
+		assert.Equal(t, &precedenceSection{Host: "localhost", Port: 8080}, cfg.Pointer)
+		assert.Nil(t, cfg.Nil, "nil pointers are not allocated")
+	})
+
+	t.Run("env without variables", func(t *testing.T) {
+		cfg := newConfig(t)
+		require.NoError(t, ParseEnv(cfg))
+
+		assert.Equal(t, "env default", cfg.Both, "envDefault is set by the env parser")
+		assert.Equal(t, "preset", cfg.Preset)
+		assert.Equal(t, &precedenceSection{Host: "localhost", Port: 8080}, cfg.Pointer)
+	})
+
+	t.Run("env with variables", func(t *testing.T) {
+		t.Setenv("PRECEDENCE_BOTH", "env")
+		t.Setenv("PRECEDENCE_PRESET", "env")
+		t.Setenv("PRECEDENCE_POINTER_PORT", "0")
+
+		cfg := newConfig(t)
+		require.NoError(t, ParseEnv(cfg))
+
+		assert.Equal(t, "env", cfg.Both)
+		assert.Equal(t, "env", cfg.Preset)
+		assert.Equal(t, &precedenceSection{Host: "localhost"}, cfg.Pointer)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(filePath, []byte("nil:\n  port: 9090\n"), 0o600))
+
+		cfg := newConfig(t)
+		require.NoError(t, ParseYaml(cfg, filePath))
+
+		assert.Equal(t, "default", cfg.Both, "envDefault is not used by ParseYaml")
+		assert.Equal(t, "preset", cfg.Preset)
+		assert.Equal(t, &precedenceSection{Host: "localhost", Port: 8080}, cfg.Pointer)
+		assert.Equal(t, &precedenceSection{Port: 9090}, cfg.Nil, "sections allocated by the file have no defaults")
+	})
+}
@@ -0,0 +1,125 @@
+package goconf
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Depender interface can be implemented by a Configer that needs other
+// configurations to be loaded first, e.g. a database pool config that reads
+// values of the server config. Load orders configurations so that dependencies
+// are registered and validated before the configurations depending on them.
+type Depender interface {
+	DependsOn() []Configer
+}
+
+// CrossValidater interface can be implemented to validate a configuration
+// against all configurations passed to Load. It is called once every
+// configuration has been registered and validated.
+type CrossValidater interface {
+	CrossValidate(configs []Configer) error
+}
+
+// orderConfigs orders configurations so that every Depender comes after its
+// dependencies. Configurations without dependencies keep their argument order.
+func orderConfigs(configs []Configer) ([]Configer, error) {
+	ordered := make([]Configer, 0, len(configs))
+	state := make([]int, len(configs)) // 0 unvisited, 1 visiting, 2 done
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		switch state[i] {
+		case 1:
+			return fmt.Errorf("dependency cycle between configurations: %v", append(path, configName(configs[i])))
+		case 2:
+			return nil
+		}
+
+		state[i] = 1
+		path = append(path, configName(configs[i]))
+
+		if d, ok := configs[i].(Depender); ok {
+			for _, dep := range d.DependsOn() {
+				j, err := findConfig(configs, dep)
+				if err != nil {
+					return fmt.Errorf("%s: %w", configName(configs[i]), err)
+				}
+
+				if err := visit(j, path); err != nil {
+					return err
+				}
+			}
+		}
+
+		state[i] = 2
+		ordered = append(ordered, configs[i])
+
+		return nil
+	}
+
+	for i := range configs {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// findConfig returns the index of the configuration matching dep. The same
+// instance matches first, otherwise configurations are matched by type.
+func findConfig(configs []Configer, dep Configer) (int, error) {
+	depType := reflect.TypeOf(dep)
+
+	// A comparable type can still hold an interface field with an uncomparable
+	// value, e.g. a slice, which makes == panic, so the value is checked
+	if depValue := reflect.ValueOf(dep); depValue.IsValid() && depValue.Comparable() {
+		for i, c := range configs {
+			if reflect.TypeOf(c) == depType && c == dep {
+				return i, nil
+			}
+		}
+	}
+
+	match := -1
+	for i, c := range configs {
+		if baseType(reflect.TypeOf(c)) != baseType(depType) {
+			continue
+		}
+
+		if match >= 0 {
+			return 0, fmt.Errorf("dependency %s matches more than one configuration", configName(dep))
+		}
+
+		match = i
+	}
+
+	if match < 0 {
+		return 0, fmt.Errorf("depends on %s which is not loaded", configName(dep))
+	}
+
+	return match, nil
+}
+
+// baseType dereferences pointer types so that Conf and *Conf match
+func baseType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// configName returns the type name of a configuration for error messages
+func configName(c Configer) string {
+	t := baseType(reflect.TypeOf(c))
+	if t == nil {
+		return "<nil>"
+	}
+
+	if t.Name() == "" {
+		return t.String()
+	}
+
+	return t.Name()
+}
@@ -0,0 +1,125 @@
+package goconf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderRecorder records the order in which test configurations are registered
+type orderRecorder struct {
+	registered []string
+}
+
+type serverConf struct {
+	recorder   *orderRecorder
+	MaxWorkers int
+}
+
+func (c *serverConf) Register() error {
+	c.recorder.registered = append(c.recorder.registered, "server")
+	c.MaxWorkers = 8
+
+	return nil
+}
+
+type poolConf struct {
+	recorder *orderRecorder
+	server   *serverConf
+	deps     []Configer
+	PoolSize int
+}
+
+func (c *poolConf) Register() error {
+	c.recorder.registered = append(c.recorder.registered, "pool")
+
+	return nil
+}
+
+func (c *poolConf) DependsOn() []Configer {
+	return c.deps
+}
+
+func (c *poolConf) CrossValidate(configs []Configer) error {
+	for _, config := range configs {
+		if server, ok := config.(*serverConf); ok && c.PoolSize > server.MaxWorkers {
+			return errors.New("pool size must not exceed max workers")
+		}
+	}
+
+	return nil
+}
+
+// valueConf is a configuration passed by value, its type is comparable but its
+// value is not when Value holds a slice
+type valueConf struct {
+	Value interface{}
+}
+
+func (valueConf) Register() error { return nil }
+
+func TestLoadDependencyOrder(t *testing.T) {
+	recorder := &orderRecorder{}
+	server := &serverConf{recorder: recorder}
+	pool := &poolConf{recorder: recorder, PoolSize: 4, deps: []Configer{server}}
+
+	require.NoError(t, Load(pool, server))
+	assert.Equal(t, []string{"server", "pool"}, recorder.registered)
+}
+
+func TestLoadCrossValidation(t *testing.T) {
+	recorder := &orderRecorder{}
+	server := &serverConf{recorder: recorder}
+	pool := &poolConf{recorder: recorder, PoolSize: 16, deps: []Configer{new(serverConf)}}
+
+	err := Load(pool, server)
+	require.EqualError(t, err, "pool size must not exceed max workers")
+	assert.Equal(t, []string{"server", "pool"}, recorder.registered, "dependencies are matched by type")
+}
+
+func TestOrderConfigs(t *testing.T) {
+	recorder := &orderRecorder{}
+	server := &serverConf{recorder: recorder}
+
+	t.Run("argument order without dependencies", func(t *testing.T) {
+		other := &serverConf{recorder: recorder}
+
+		ordered, err := orderConfigs([]Configer{other, server})
+		require.NoError(t, err)
+		assert.Equal(t, []Configer{other, server}, ordered)
+	})
+
+	t.Run("missing dependency", func(t *testing.T) {
+		pool := &poolConf{deps: []Configer{server}}
+
+		_, err := orderConfigs([]Configer{pool})
+		require.EqualError(t, err, "poolConf: depends on serverConf which is not loaded")
+	})
+
+	t.Run("ambiguous dependency", func(t *testing.T) {
+		pool := &poolConf{deps: []Configer{new(serverConf)}}
+
+		_, err := orderConfigs([]Configer{pool, server, &serverConf{}})
+		require.EqualError(t, err, "poolConf: dependency serverConf matches more than one configuration")
+	})
+
+	t.Run("uncomparable value of a comparable type", func(t *testing.T) {
+		dep := valueConf{Value: []string{"a"}}
+		pool := &poolConf{deps: []Configer{dep}}
+
+		ordered, err := orderConfigs([]Configer{pool, valueConf{Value: []string{"a"}}})
+		require.NoError(t, err)
+		assert.Equal(t, []Configer{valueConf{Value: []string{"a"}}, pool}, ordered, "matched by type")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		a := &poolConf{}
+		b := &poolConf{deps: []Configer{a}}
+		a.deps = []Configer{b}
+
+		_, err := orderConfigs([]Configer{a, b})
+		require.EqualError(t, err, "dependency cycle between configurations: [poolConf poolConf poolConf]")
+	})
+}
@@ -0,0 +1,176 @@
+package goconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// PrintMode defines which fields are printed
+type PrintMode string
+
+const (
+	// PrintModeAll prints all fields (default)
+	PrintModeAll PrintMode = "all"
+	// PrintModeChanged prints only the fields whose values differ from their
+	// declared default, or from the zero value if the field has no default
+	PrintModeChanged PrintMode = "changed"
+)
+
+var currentPrintMode = PrintModeAll
+
+// SetPrintMode sets which fields are printed by Load
+func SetPrintMode(mode PrintMode) {
+	currentPrintMode = mode
+}
+
+// changedFields returns the fields that differ from their defaults.
+// Nested structs without changed fields are dropped.
+func changedFields(fields []Field) []Field {
+	changed := make([]Field, 0, len(fields))
+
+	for _, f := range fields {
+		if f.IsStruct() {
+			nested := changedFields(f.Fields)
+			if len(nested) == 0 {
+				continue
+			}
+
+			f.Fields = nested
+			changed = append(changed, f)
+
+			continue
+		}
+
+		if !f.IsDefault {
+			changed = append(changed, f)
+		}
+	}
+
+	return changed
+}
+
+// ChangeType describes how a field differs between two configurations
+type ChangeType string
+
+const (
+	// ChangeAdded means the field only exists in the new configuration
+	ChangeAdded ChangeType = "added"
+	// ChangeRemoved means the field only exists in the old configuration
+	ChangeRemoved ChangeType = "removed"
+	// ChangeModified means the field value differs between the configurations
+	ChangeModified ChangeType = "changed"
+)
+
+// Change describes a single field that differs between two configurations.
+// Before and After hold SensitiveDataMaskString for secret fields.
+type Change struct {
+	Key    string      `json:"key"`
+	Type   ChangeType  `json:"type"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+	Secret bool        `json:"secret,omitempty"`
+}
+
+// Diff compares two configurations, e.g. before and after a reload, and returns
+// the added, removed and changed fields in field order. Fields are keyed using
+// the current key naming mode. Secret fields are compared using their real values
+// but their values are masked in the returned changes.
+//
+// Usage Example:
+//
+//	before := AppConfig
+//	if err := goconf.Load(new(Config)); err != nil {
+//	    // Handle reload error
+//	}
+//
+//	if err := goconf.PrintDiff(os.Stdout, goconf.Diff(before, AppConfig)); err != nil {
+//	    // Handle print error
+//	}
+func Diff(before, after interface{}) []Change {
+	oldFields := FlattenFields(buildFields(currentKeyNaming, structValue(before)))
+	newFields := FlattenFields(buildFields(currentKeyNaming, structValue(after)))
+
+	oldByKey := make(map[string]Field, len(oldFields))
+	for _, f := range oldFields {
+		oldByKey[f.Key] = f
+	}
+
+	newByKey := make(map[string]Field, len(newFields))
+	for _, f := range newFields {
+		newByKey[f.Key] = f
+	}
+
+	var changes []Change
+
+	for _, f := range oldFields {
+		if _, ok := newByKey[f.Key]; !ok {
+			changes = append(changes, Change{Key: f.Key, Type: ChangeRemoved, Before: f.Value, Secret: f.Secret})
+		}
+	}
+
+	for _, f := range newFields {
+		old, ok := oldByKey[f.Key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Key: f.Key, Type: ChangeAdded, After: f.Value, Secret: f.Secret})
+		case !reflect.DeepEqual(old.raw, f.raw):
+			changes = append(changes, Change{
+				Key:    f.Key,
+				Type:   ChangeModified,
+				Before: old.Value,
+				After:  f.Value,
+				Secret: f.Secret || old.Secret,
+			})
+		}
+	}
+
+	return changes
+}
+
+// PrintDiff writes the changes returned by Diff to w. The changes are logged
+// as JSON when the output format is OutputFormatJSON and printed as a table otherwise.
+func PrintDiff(w io.Writer, changes []Change) error {
+	if currentOutputFormat == OutputFormatJSON {
+		jsonData, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal diff to JSON: %w", err)
+		}
+
+		logger := log.New(w, "", log.LstdFlags)
+		logger.Println(string(jsonData))
+
+		return nil
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.Header("Config", "Change", "Before", "After")
+
+	data := make([][]string, 0, len(changes))
+	for _, c := range changes {
+		data = append(data, []string{c.Key, string(c.Type), diffValue(c.Before), diffValue(c.After)})
+	}
+
+	if err := table.Bulk(data); err != nil {
+		return fmt.Errorf("failed to add table data: %w", err)
+	}
+
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	return nil
+}
+
+// diffValue formats a value of a Change for the table output
+func diffValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	return formatValue(reflect.ValueOf(value))
+}
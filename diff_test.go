@@ -0,0 +1,116 @@
+package goconf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintModeChanged(t *testing.T) {
+	originalMode := currentPrintMode
+	defer func() { currentPrintMode = originalMode }()
+
+	SetPrintMode(PrintModeChanged)
+
+	type config struct {
+		Port     int    `envDefault:"8080"`
+		LogLevel string `envDefault:"info"`
+		Region   string
+		Database struct {
+			Host string `default:"localhost"`
+		}
+		Cache struct {
+			Host string `default:"localhost"`
+		}
+	}
+
+	cfg := config{Port: 8080, LogLevel: "debug"}
+	cfg.Database.Host = "db.internal"
+	cfg.Cache.Host = "localhost"
+
+	var buf bytes.Buffer
+	require.NoError(t, printConfig(&buf, printerMock(gomock.NewController(t), cfg).(Printer)))
+
+	assert.Equal(t, `┌───────────────┬─────────────┐
+│    CONFIG     │    VALUE    │
+├───────────────┼─────────────┤
+│ LogLevel      │ debug       │
+│ Database.Host │ db.internal │
+└───────────────┴─────────────┘
+`, buf.String())
+}
+
+func TestDiff(t *testing.T) {
+	type database struct {
+		Host     string
+		Password string `secret:"true"`
+	}
+
+	type oldConfig struct {
+		Name     string
+		Port     int
+		Legacy   bool
+		Database database
+	}
+
+	type newConfig struct {
+		Name     string
+		Port     int
+		Database database
+		Debug    bool
+	}
+
+	before := oldConfig{Name: "app", Port: 8080, Legacy: true, Database: database{Host: "db", Password: "old-secret"}}
+	after := &newConfig{Name: "app", Port: 9090, Database: database{Host: "db", Password: "new-secret"}, Debug: true}
+
+	changes := Diff(before, after)
+
+	assert.Equal(t, []Change{
+		{Key: "Legacy", Type: ChangeRemoved, Before: true},
+		{Key: "Port", Type: ChangeModified, Before: 8080, After: 9090},
+		{Key: "Database.Password", Type: ChangeModified, Before: SensitiveDataMaskString, After: SensitiveDataMaskString, Secret: true},
+		{Key: "Debug", Type: ChangeAdded, After: true},
+	}, changes)
+
+	assert.Empty(t, Diff(before, before))
+}
+
+func TestPrintDiff(t *testing.T) {
+	originalFormat := currentOutputFormat
+	defer func() { currentOutputFormat = originalFormat }()
+
+	changes := []Change{
+		{Key: "Port", Type: ChangeModified, Before: 8080, After: 9090},
+		{Key: "Password", Type: ChangeModified, Before: SensitiveDataMaskString, After: SensitiveDataMaskString, Secret: true},
+		{Key: "Debug", Type: ChangeAdded, After: true},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		SetOutputFormat(OutputFormatTable)
+
+		var buf bytes.Buffer
+		require.NoError(t, PrintDiff(&buf, changes))
+
+		assert.Equal(t, `┌──────────┬─────────┬─────────────────┬─────────────────┐
+│  CONFIG  │ CHANGE  │     BEFORE      │      AFTER      │
+├──────────┼─────────┼─────────────────┼─────────────────┤
+│ Port     │ changed │ 8080            │ 9090            │
+│ Password │ changed │ *************** │ *************** │
+│ Debug    │ added   │                 │ true            │
+└──────────┴─────────┴─────────────────┴─────────────────┘
+`, buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		SetOutputFormat(OutputFormatJSON)
+
+		var buf bytes.Buffer
+		require.NoError(t, PrintDiff(&buf, changes))
+
+		assert.Contains(t, buf.String(), `"key": "Port"`)
+		assert.Contains(t, buf.String(), `"type": "added"`)
+	})
+}
@@ -0,0 +1,278 @@
+package goconf
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"gopkg.in/yaml.v3"
+)
+
+// tableRows returns the leaf fields as key, value rows
+func tableRows(fields []Field) [][]string {
+	var data [][]string
+
+	for _, f := range FlattenFields(fields) {
+		data = append(data, []string{f.Key, f.ValueString()})
+	}
+
+	return data
+}
+
+func renderTable(w io.Writer, fields []Field) error {
+	table := tablewriter.NewWriter(w)
+
+	data := tableRows(fields)
+	if currentShowMetadata {
+		table.Header("Config", "Value", "Description", "Default", "Is Default")
+		data = metadataRows(fields)
+	} else {
+		table.Header("Config", "Value")
+	}
+
+	if err := table.Bulk(data); err != nil {
+		return fmt.Errorf("failed to add table data: %w", err)
+	}
+
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	return nil
+}
+
+// jsonMap converts the fields into a map for JSON marshaling.
+// Env var names are flat, so with KeyNamingEnv nested structs are merged into
+// the parent map and fields without an env tag are keyed by their Go field path.
+func jsonMap(naming KeyNaming, fields []Field) map[string]interface{} {
+	configMap := make(map[string]interface{})
+
+	if naming == KeyNamingEnv {
+		for _, f := range FlattenFields(fields) {
+			configMap[f.Key] = f.Value
+		}
+
+		return configMap
+	}
+
+	for _, f := range fields {
+		// Handle nested structs recursively
+		if f.IsStruct() {
+			configMap[f.Name] = jsonMap(naming, f.Fields)
+			continue
+		}
+
+		configMap[f.Name] = f.Value
+	}
+
+	return configMap
+}
+
+func renderJSON(w io.Writer, fields []Field) error {
+	configMap := jsonMap(currentKeyNaming, fields)
+	if currentShowMetadata {
+		configMap[metadataKey] = metadataMap(fields)
+	}
+
+	jsonData, err := json.MarshalIndent(configMap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config to JSON: %w", err)
+	}
+
+	// Create a logger that writes with timestamp
+	logger := log.New(w, "", log.LstdFlags)
+	logger.Println(string(jsonData))
+
+	return nil
+}
+
+// yamlNode builds an ordered YAML mapping node from the fields.
+// Keys always use the yaml tag names so the output can be loaded with ParseYaml.
+func yamlNode(fields []Field) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, f := range fields {
+		// Absent keys load as unset Optional fields
+		if f.Unset {
+			continue
+		}
+
+		name := f.Path[len(f.Path)-1]
+		if tag := tagName(f.Tags, "yaml"); tag != "" {
+			name = tag
+		}
+
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+		value := &yaml.Node{}
+
+		if f.IsStruct() {
+			nested, err := yamlNode(f.Fields)
+			if err != nil {
+				return nil, err
+			}
+			value = nested
+		} else if err := value.Encode(f.Value); err != nil {
+			return nil, fmt.Errorf("failed to encode field %s: %w", strings.Join(f.Path, "."), err)
+		}
+
+		node.Content = append(node.Content, key, value)
+	}
+
+	return node, nil
+}
+
+func renderYAML(w io.Writer, fields []Field) error {
+	node, err := yamlNode(fields)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(node); err != nil {
+		return fmt.Errorf("failed to marshal config to YAML: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to marshal config to YAML: %w", err)
+	}
+
+	return nil
+}
+
+// renderDotenv writes KEY=value lines for the fields read from environment
+// variables, formatted so that ParseEnv reads the same values back. Fields without
+// an env tag and unset Optional fields are skipped.
+func renderDotenv(w io.Writer, fields []Field) error {
+	var b strings.Builder
+
+	for _, f := range FlattenFields(fields) {
+		if f.Source == "" || f.Unset {
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s=%s\n", f.Source, quoteValue(envValue(f), " \t#'\"\\$=\n"))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// envValue formats the value of a field the way the env parser reads it, using
+// the envSeparator and envKeyValSeparator tags of the field. Secret fields keep
+// their mask.
+func envValue(f Field) string {
+	if f.Secret || f.Value == nil {
+		return f.ValueString()
+	}
+
+	separator := f.Tags.Get("envSeparator")
+	if separator == "" {
+		separator = ","
+	}
+
+	keyValSeparator := f.Tags.Get("envKeyValSeparator")
+	if keyValSeparator == "" {
+		keyValSeparator = ":"
+	}
+
+	return formatEnvValue(reflect.ValueOf(f.Value), separator, keyValSeparator)
+}
+
+// formatEnvValue formats a value the way the env parser reads it: text marshalers
+// as their text, durations like time.Duration.String, slice items joined by
+// separator and map entries as key, keyValSeparator, value joined by separator
+func formatEnvValue(value reflect.Value, separator, keyValSeparator string) string {
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return ""
+	}
+
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	switch {
+	case value.Type() == durationType:
+		return time.Duration(value.Int()).String()
+	case value.Kind() == reflect.Ptr:
+		if loc, ok := value.Interface().(*time.Location); ok {
+			return loc.String()
+		}
+
+		return formatEnvValue(value.Elem(), separator, keyValSeparator)
+	case value.Kind() == reflect.Slice:
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i] = formatEnvValue(value.Index(i), separator, keyValSeparator)
+		}
+
+		return strings.Join(items, separator)
+	case value.Kind() == reflect.Map:
+		pairs := make([]string, 0, value.Len())
+		for iter := value.MapRange(); iter.Next(); {
+			pairs = append(pairs, formatEnvValue(iter.Key(), separator, keyValSeparator)+
+				keyValSeparator+formatEnvValue(iter.Value(), separator, keyValSeparator))
+		}
+
+		sort.Strings(pairs)
+
+		return strings.Join(pairs, separator)
+	default:
+		return formatValue(value)
+	}
+}
+
+func renderLogfmt(w io.Writer, fields []Field) error {
+	rows := tableRows(fields)
+
+	pairs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		pairs = append(pairs, row[0]+"="+quoteValue(row[1], " \t\"=\n"))
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(pairs, " "))
+
+	return err
+}
+
+func renderMarkdown(w io.Writer, fields []Field) error {
+	var b strings.Builder
+
+	b.WriteString("| Config | Value |\n")
+	b.WriteString("|--------|-------|\n")
+
+	for _, row := range tableRows(fields) {
+		fmt.Fprintf(&b, "| %s | %s |\n", escapeMarkdown(row[0]), escapeMarkdown(row[1]))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// quoteValue double quotes value if it is empty or contains any of the special characters
+func quoteValue(value, special string) string {
+	if value != "" && !strings.ContainsAny(value, special) {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// escapeMarkdown escapes characters that would break a markdown table cell
+func escapeMarkdown(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(value)
+}
@@ -0,0 +1,206 @@
+package goconf
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/wgarunap/goconf/mocks"
+)
+
+type formatsConfig struct {
+	AppName  string `env:"APP_NAME" yaml:"app_name"`
+	Port     int    `env:"PORT" yaml:"port"`
+	Internal string `yaml:"internal"`
+	Database struct {
+		Host     string `env:"HOST" yaml:"host"`
+		Password string `env:"PASSWORD" yaml:"password" secret:"true"`
+	} `yaml:"database" envPrefix:"DB_"`
+}
+
+func newFormatsConfig() formatsConfig {
+	cfg := formatsConfig{AppName: "My App", Port: 8080, Internal: "a|b"}
+	cfg.Database.Host = "localhost"
+	cfg.Database.Password = "db-secret"
+
+	return cfg
+}
+
+// captureStdout returns everything fn writes to os.Stdout
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	oldStdOut := os.Stdout
+	os.Stdout = w
+
+	fnErr := fn()
+
+	w.Close()
+	os.Stdout = oldStdOut
+
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(r)
+	require.NoError(t, err)
+	require.NoError(t, fnErr)
+
+	return buf.String()
+}
+
+func TestLoadWithAdditionalOutputFormats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	originalFormat := currentOutputFormat
+	defer func() { currentOutputFormat = originalFormat }()
+
+	tests := map[string]struct {
+		outputFormat OutputFormat
+		expected     string
+	}{
+		"yaml": {
+			outputFormat: OutputFormatYAML,
+			expected: `app_name: My App
+port: 8080
+internal: a|b
+database:
+  host: localhost
+  password: '***************'
+`,
+		},
+		"dotenv": {
+			outputFormat: OutputFormatDotenv,
+			expected: `APP_NAME="My App"
+PORT=8080
+DB_HOST=localhost
+DB_PASSWORD=***************
+`,
+		},
+		"logfmt": {
+			outputFormat: OutputFormatLogfmt,
+			expected: `AppName="My App" Port=8080 Internal=a|b Database.Host=localhost Database.Password=***************
+`,
+		},
+		"markdown": {
+			outputFormat: OutputFormatMarkdown,
+			expected: `| Config | Value |
+|--------|-------|
+| AppName | My App |
+| Port | 8080 |
+| Internal | a\|b |
+| Database.Host | localhost |
+| Database.Password | *************** |
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			SetOutputFormat(test.outputFormat)
+
+			output := captureStdout(t, func() error {
+				return Load(printerMock(ctrl, newFormatsConfig()))
+			})
+
+			assert.Equal(t, test.expected, output)
+			assert.NotContains(t, output, "db-secret")
+		})
+	}
+}
+
+func TestYAMLOutputCanBeParsed(t *testing.T) {
+	node, err := yamlNode(buildFields(KeyNamingField, reflect.ValueOf(newFormatsConfig())))
+	require.NoError(t, err)
+
+	data, err := yaml.Marshal(node)
+	require.NoError(t, err)
+
+	var cfg formatsConfig
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+
+	expected := newFormatsConfig()
+	expected.Database.Password = SensitiveDataMaskString
+	assert.Equal(t, expected, cfg)
+}
+
+type dotenvConfig struct {
+	Name     string            `env:"DOTENV_NAME"`
+	Timeout  time.Duration     `env:"DOTENV_TIMEOUT"`
+	Hosts    []string          `env:"DOTENV_HOSTS"`
+	Ports    []int             `env:"DOTENV_PORTS" envSeparator:";"`
+	Labels   map[string]string `env:"DOTENV_LABELS"`
+	Weights  map[string]int    `env:"DOTENV_WEIGHTS" envSeparator:"|" envKeyValSeparator:"="`
+	Interval *time.Duration    `env:"DOTENV_INTERVAL"`
+	Networks []CIDR            `env:"DOTENV_NETWORKS"`
+}
+
+func (c *dotenvConfig) Register() error { return ParseEnv(c) }
+
+func (c *dotenvConfig) Print() interface{} { return *c }
+
+func TestDotenvOutputCanBeParsed(t *testing.T) {
+	originalFormat := currentOutputFormat
+	defer func() { currentOutputFormat = originalFormat }()
+
+	SetOutputFormat(OutputFormatDotenv)
+
+	interval := 90 * time.Second
+	t.Setenv("DOTENV_NAME", "say \"hi\" #1")
+	t.Setenv("DOTENV_TIMEOUT", "1m30s")
+	t.Setenv("DOTENV_HOSTS", "a.example.com,b.example.com")
+	t.Setenv("DOTENV_PORTS", "80;443")
+	t.Setenv("DOTENV_LABELS", "team:core,tier:web")
+	t.Setenv("DOTENV_WEIGHTS", "a=1|b=2")
+	t.Setenv("DOTENV_INTERVAL", interval.String())
+	t.Setenv("DOTENV_NETWORKS", "10.0.0.0/8,192.168.1.0/24")
+
+	loaded := &dotenvConfig{}
+	output := captureStdout(t, func() error { return Load(loaded) })
+
+	values, err := readEnvFile(writeCheckFile(t, ".env", output))
+	require.NoError(t, err)
+
+	require.Len(t, values, 8)
+	for key, value := range values {
+		t.Setenv(key, value.value)
+	}
+
+	var parsed dotenvConfig
+	require.NoError(t, ParseEnv(&parsed))
+	assert.Equal(t, *loaded, parsed)
+	assert.Equal(t, interval, *parsed.Interval)
+	assert.Equal(t, "10.0.0.0/8,192.168.1.0/24", values["DOTENV_NETWORKS"].value)
+}
+
+func TestQuoteValue(t *testing.T) {
+	assert.Equal(t, "plain", quoteValue("plain", " "))
+	assert.Equal(t, `""`, quoteValue("", " "))
+	assert.Equal(t, `"a b"`, quoteValue("a b", " "))
+	assert.Equal(t, `"say \"hi\"\n"`, quoteValue("say \"hi\"\n", " \"\n"))
+}
+
+// printerMock returns a Configer that registers successfully and prints the given config
+func printerMock(ctrl *gomock.Controller, config interface{}) Configer {
+	mockConfiger := mocks.NewMockConfiger(ctrl)
+	mockPrinter := mocks.NewMockPrinter(ctrl)
+
+	mockConfiger.EXPECT().Register().Return(nil).AnyTimes()
+	mockPrinter.EXPECT().Print().Return(config).AnyTimes()
+
+	return &struct {
+		*mocks.MockConfiger
+		*mocks.MockPrinter
+	}{
+		MockConfiger: mockConfiger,
+		MockPrinter:  mockPrinter,
+	}
+}
@@ -0,0 +1,184 @@
+package goconf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// Loader loads and checks configurations like Load and CheckFile, validating them
+// with its own Validator. This lets services, or tests, keep custom rules apart
+// instead of registering them on the package level validator. The other settings,
+// such as the output format and the load concurrency, are shared with Load.
+// A Loader is safe for concurrent use.
+//
+// Usage Example:
+//
+//	v := goconf.NewValidator()
+//	v.RegisterAlias("listen_port", "gte=1024,lte=65535")
+//
+//	loader := goconf.NewLoader(goconf.WithValidator(v))
+//	if err := loader.Load(new(Config)); err != nil {
+//	    log.Fatal(loader.FormatError(err))
+//	}
+type Loader struct {
+	validator *Validator
+	// locale is the locale of the validation messages, empty for the selected
+	// locale of the Validator
+	locale string
+}
+
+// LoaderOption configures a Loader created by NewLoader
+type LoaderOption func(*Loader)
+
+// WithValidator sets the Validator used by the Loader, e.g. one created by
+// NewValidator holding the custom rules of a service
+func WithValidator(v *Validator) LoaderOption {
+	return func(l *Loader) {
+		if v != nil {
+			l.validator = v
+		}
+	}
+}
+
+// WithLocale selects the locale of the validation messages of the Loader, rendered
+// by its Validator with Loader.FormatError, Loader.Translate and Loader.CheckFile.
+// Unlike Validator.SetLocale it does not change the messages of other users of the
+// Validator. The locale must be registered using RegisterLocale, messages of
+// unregistered locales fall back to the selected locale of the Validator.
+func WithLocale(locale string) LoaderOption {
+	return func(l *Loader) {
+		l.locale = locale
+	}
+}
+
+// NewLoader creates a Loader. Without options it uses DefaultValidator, like Load.
+func NewLoader(options ...LoaderOption) *Loader {
+	l := &Loader{validator: defaultValidator}
+	for _, option := range options {
+		option(l)
+	}
+
+	return l
+}
+
+// defaultLoader is the Loader used by Load, LoadContext and CheckFile
+var defaultLoader = NewLoader()
+
+// Validator returns the Validator used by the Loader
+func (l *Loader) Validator() *Validator {
+	return l.validator
+}
+
+// Load registers, validates, and prints one or more configuration objects, see
+// the package level Load. It is equivalent to LoadContext with context.Background().
+func (l *Loader) Load(configs ...Configer) error {
+	return l.LoadContext(context.Background(), configs...)
+}
+
+// LoadContext registers, validates, and prints one or more configuration objects,
+// see the package level LoadContext. The context passed to RegisterContext and
+// ValidateContext carries the Validator of the Loader, use ValidatorFromContext to
+// validate with it. The `warn` tags are checked with the same Validator.
+func (l *Loader) LoadContext(ctx context.Context, configs ...Configer) error {
+	ordered, err := orderConfigs(configs)
+	if err != nil {
+		return err
+	}
+
+	if ValidatorFromContext(ctx) != l.validator {
+		ctx = context.WithValue(ctx, validatorContextKey{}, l.validator)
+	}
+
+	if currentLoadConcurrency == 1 {
+		for _, c := range ordered {
+			if err := loadConfig(ctx, c); err != nil {
+				return err
+			}
+		}
+	} else if err := loadConcurrently(ctx, ordered, currentLoadConcurrency); err != nil {
+		return err
+	}
+
+	for _, c := range ordered {
+		cv, ok := c.(CrossValidater)
+		if ok {
+			if err := cv.CrossValidate(ordered); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range ordered {
+		f, ok := c.(Finalizer)
+		if ok {
+			if err := f.Finalize(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return context.Cause(ctx)
+	}
+
+	for _, c := range ordered {
+		for _, warning := range collectWarnings(c, l.validator) {
+			currentWarningHandler(warning)
+		}
+
+		p, ok := c.(Printer)
+		if ok {
+			if err := printConfig(os.Stdout, p); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckFile loads a YAML file into config and validates it with the Validator of
+// the Loader, reporting its messages in the locale of the Loader. See the package
+// level CheckFile.
+func (l *Loader) CheckFile(config interface{}, filePath string, envFiles ...string) error {
+	values := reflect.ValueOf(config)
+	if values.Kind() != reflect.Ptr || values.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config must be a pointer to a struct, got %T", config)
+	}
+
+	return checkFile(l, values, filePath, envFiles)
+}
+
+// Translate returns the message of every validation failure in err in the locale
+// of the Loader, see Validator.Translate
+func (l *Loader) Translate(err error) map[string]string {
+	return l.validator.translate(err, l.locale)
+}
+
+// FormatError renders err as a human readable message in the locale of the Loader,
+// see Validator.FormatError
+func (l *Loader) FormatError(err error) string {
+	return l.validator.formatError(err, l.locale)
+}
+
+// validatorContextKey is the context key of the Validator of a Loader
+type validatorContextKey struct{}
+
+// ValidatorFromContext returns the Validator of the Loader running LoadContext, as
+// carried by the context passed to RegisterContext and ValidateContext. It returns
+// DefaultValidator if ctx does not come from a Loader.
+//
+// Usage Example:
+//
+//	func (c *Config) ValidateContext(ctx context.Context) error {
+//	    return goconf.ValidatorFromContext(ctx).Struct(c)
+//	}
+func ValidatorFromContext(ctx context.Context) *Validator {
+	if v, ok := ctx.Value(validatorContextKey{}).(*Validator); ok {
+		return v
+	}
+
+	return defaultValidator
+}
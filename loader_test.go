@@ -0,0 +1,106 @@
+package goconf
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/validator/v10"
+	frtranslations "github.com/go-playground/validator/v10/translations/fr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loaderConfig struct {
+	Port int `yaml:"port" env:"LOADER_PORT" validate:"loader_port" warn:"loader_even"`
+}
+
+func (c *loaderConfig) Register() error { return ParseEnv(c) }
+
+func (c *loaderConfig) ValidateContext(ctx context.Context) error {
+	return ValidatorFromContext(ctx).Struct(c)
+}
+
+func (c *loaderConfig) Print() interface{} { return *c }
+
+func newLoaderValidator(t *testing.T) *Validator {
+	t.Helper()
+
+	v := NewValidator()
+	v.RegisterAlias("loader_port", "gte=1024")
+	require.NoError(t, v.RegisterValidation("loader_even", func(fl validator.FieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}))
+
+	return v
+}
+
+func TestNewLoader(t *testing.T) {
+	assert.Same(t, DefaultValidator(), NewLoader().Validator())
+	assert.Same(t, DefaultValidator(), NewLoader(WithValidator(nil)).Validator())
+
+	v := NewValidator()
+	assert.Same(t, v, NewLoader(WithValidator(v)).Validator())
+
+	assert.Same(t, DefaultValidator(), ValidatorFromContext(context.Background()))
+}
+
+func TestLoaderLoad(t *testing.T) {
+	loader := NewLoader(WithValidator(newLoaderValidator(t)))
+
+	t.Setenv("LOADER_PORT", "8080")
+	assert.NoError(t, loader.Load(&loaderConfig{}))
+
+	t.Setenv("LOADER_PORT", "80")
+	err := loader.Load(&loaderConfig{})
+	assert.Equal(t, "validation failed:\n  - loaderConfig.Port: Port must be 1,024 or greater", loader.Validator().FormatError(err))
+}
+
+func TestLoaderWarnings(t *testing.T) {
+	v := newLoaderValidator(t)
+
+	assert.Equal(t, []string{"Port=8081 does not satisfy loader_even"}, collectWarnings(&loaderConfig{Port: 8081}, v))
+	assert.Empty(t, collectWarnings(&loaderConfig{Port: 8080}, v))
+}
+
+func TestLoaderCheckFile(t *testing.T) {
+	loader := NewLoader(WithValidator(newLoaderValidator(t)))
+
+	yamlFile := writeCheckFile(t, "config.yaml", "port: 80\n")
+
+	var cfg loaderConfig
+	err := loader.CheckFile(&cfg, yamlFile)
+	assert.Equal(t, CheckErrors{
+		{File: yamlFile, Line: 1, Column: 1, Field: "port", Message: "Port must be 1,024 or greater"},
+	}, err)
+
+	err = loader.CheckFile(cfg, yamlFile)
+	assert.EqualError(t, err, "config must be a pointer to a struct, got goconf.loaderConfig")
+}
+
+func TestLoaderLocale(t *testing.T) {
+	RegisterLocale(fr.New(), frtranslations.RegisterDefaultTranslations)
+
+	v := NewValidator()
+	french := NewLoader(WithValidator(v), WithLocale("fr"))
+	english := NewLoader(WithValidator(v))
+
+	err := v.Struct(translationConfig{Port: 8080, Timeout: time.Second, Level: "info"})
+	require.Error(t, err)
+
+	assert.Equal(t, "validation failed:\n  - translationConfig.Name: Name est un champ obligatoire", french.FormatError(err))
+	assert.Equal(t, map[string]string{"translationConfig.Name": "Name est un champ obligatoire"}, french.Translate(err))
+	assert.Equal(t, "validation failed:\n  - translationConfig.Name: Name is a required field", english.FormatError(err),
+		"the locale of a loader does not change the validator")
+	assert.Equal(t, "validation failed:\n  - translationConfig.Name: Name is a required field", v.FormatError(err))
+
+	unknown := NewLoader(WithValidator(v), WithLocale("xx"))
+	assert.Equal(t, "validation failed:\n  - translationConfig.Name: Name is a required field", unknown.FormatError(err))
+
+	yamlFile := writeCheckFile(t, "config.yaml", "port: 8080\ntimeout: 2s\nlevel: info\n")
+	var cfg translationConfig
+	assert.Equal(t, CheckErrors{
+		{File: yamlFile, Field: "name", Message: "Name est un champ obligatoire"},
+	}, french.CheckFile(&cfg, yamlFile))
+}
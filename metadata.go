@@ -0,0 +1,92 @@
+package goconf
+
+import "reflect"
+
+const (
+	// descriptionTag is the struct tag holding a human readable field description
+	descriptionTag = "desc"
+	// metadataKey is the JSON key holding field metadata when metadata output is enabled
+	metadataKey = "_metadata"
+)
+
+var currentShowMetadata bool
+
+// SetShowMetadata enables field metadata in the printed configuration.
+// The table output gets Description, Default and Is Default columns and the JSON
+// output gets a "_metadata" block keyed by field, so the startup dump doubles as
+// documentation.
+//
+// Descriptions are read from the `desc` tag and defaults from the `envDefault`
+// or `default` tags:
+//
+//	type Config struct {
+//	    MaxIdleConns int `env:"MAX_IDLE_CONNS" envDefault:"10" desc:"Maximum idle database connections"`
+//	}
+func SetShowMetadata(show bool) {
+	currentShowMetadata = show
+}
+
+// fieldMetadata is the JSON representation of the metadata of a single field
+type fieldMetadata struct {
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	IsDefault   bool   `json:"is_default"`
+}
+
+// defaultValue returns the declared default of a field from the `envDefault`
+// or `default` tags
+func defaultValue(field reflect.StructField) (string, bool) {
+	if value, ok := field.Tag.Lookup("envDefault"); ok {
+		return value, true
+	}
+
+	return field.Tag.Lookup("default")
+}
+
+// setMetadata fills the description and default related fields of f.
+// Fields without a declared default are compared against their zero value.
+func setMetadata(f *Field, structField reflect.StructField, value reflect.Value) {
+	f.Description = structField.Tag.Get(descriptionTag)
+	f.Default, f.HasDefault = defaultValue(structField)
+
+	if f.HasDefault {
+		f.IsDefault = isDefaultValue(value, f.Default)
+	} else {
+		f.IsDefault = value.IsZero()
+	}
+
+	if f.Secret && f.Default != "" {
+		f.Default = SensitiveDataMaskString
+	}
+}
+
+// metadataRows returns the leaf fields as key, value, description, default, is default rows
+func metadataRows(fields []Field) [][]string {
+	var data [][]string
+
+	for _, f := range FlattenFields(fields) {
+		isDefault := "no"
+		if f.IsDefault {
+			isDefault = "yes"
+		}
+
+		data = append(data, []string{f.Key, f.ValueString(), f.Description, f.Default, isDefault})
+	}
+
+	return data
+}
+
+// metadataMap returns the metadata of the leaf fields keyed by field key
+func metadataMap(fields []Field) map[string]fieldMetadata {
+	metadata := make(map[string]fieldMetadata)
+
+	for _, f := range FlattenFields(fields) {
+		metadata[f.Key] = fieldMetadata{
+			Description: f.Description,
+			Default:     f.Default,
+			IsDefault:   f.IsDefault,
+		}
+	}
+
+	return metadata
+}
@@ -0,0 +1,91 @@
+package goconf
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type metadataConfig struct {
+	MaxIdleConns int           `env:"MAX_IDLE_CONNS" envDefault:"10" desc:"Maximum idle database connections"`
+	Timeout      time.Duration `yaml:"timeout" default:"5s" desc:"Request timeout"`
+	LogLevel     string        `env:"LOG_LEVEL" envDefault:"info"`
+	Region       string        `env:"REGION"`
+	APIKey       string        `env:"API_KEY" envDefault:"dev-key" secret:"true"`
+}
+
+func TestSetMetadata(t *testing.T) {
+	cfg := metadataConfig{MaxIdleConns: 10, Timeout: 30 * time.Second, LogLevel: "debug", APIKey: "dev-key"}
+
+	fields := buildFields(KeyNamingField, reflect.ValueOf(cfg))
+	require.Len(t, fields, 5)
+
+	assert.Equal(t, "Maximum idle database connections", fields[0].Description)
+	assert.Equal(t, "10", fields[0].Default)
+	assert.True(t, fields[0].HasDefault)
+	assert.True(t, fields[0].IsDefault)
+
+	assert.Equal(t, "5s", fields[1].Default)
+	assert.False(t, fields[1].IsDefault, "30s differs from the 5s default")
+
+	assert.False(t, fields[2].IsDefault, "debug differs from the info default")
+
+	assert.False(t, fields[3].HasDefault)
+	assert.True(t, fields[3].IsDefault, "zero value without a declared default")
+
+	assert.Equal(t, SensitiveDataMaskString, fields[4].Default, "secret defaults are masked")
+	assert.True(t, fields[4].IsDefault)
+}
+
+func TestShowMetadata(t *testing.T) {
+	originalFormat := currentOutputFormat
+	originalShowMetadata := currentShowMetadata
+	defer func() {
+		currentOutputFormat = originalFormat
+		currentShowMetadata = originalShowMetadata
+	}()
+
+	SetShowMetadata(true)
+	cfg := metadataConfig{MaxIdleConns: 10, Timeout: 5 * time.Second, LogLevel: "info", APIKey: "prod-key"}
+
+	t.Run("table", func(t *testing.T) {
+		SetOutputFormat(OutputFormatTable)
+
+		var buf bytes.Buffer
+		require.NoError(t, printConfig(&buf, printerMock(gomock.NewController(t), cfg).(Printer)))
+
+		output := buf.String()
+		assert.Contains(t, output, "DESCRIPTION")
+		assert.Contains(t, output, "IS DEFAULT")
+		assert.Contains(t, output, "│ MaxIdleConns │ 10              │ Maximum idle database connections │ 10              │ yes        │")
+		assert.NotContains(t, output, "prod-key")
+		assert.NotContains(t, output, "dev-key")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		SetOutputFormat(OutputFormatJSON)
+
+		var buf bytes.Buffer
+		require.NoError(t, printConfig(&buf, printerMock(gomock.NewController(t), cfg).(Printer)))
+
+		output := buf.String()
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output[strings.Index(output, "{"):]), &result))
+
+		metadata, ok := result[metadataKey].(map[string]interface{})
+		require.True(t, ok, "JSON output should contain a metadata block")
+		assert.Equal(t, map[string]interface{}{
+			"description": "Request timeout",
+			"default":     "5s",
+			"is_default":  true,
+		}, metadata["Timeout"])
+		assert.Equal(t, map[string]interface{}{"is_default": true}, metadata["Region"])
+	})
+}
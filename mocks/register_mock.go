@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -47,6 +48,117 @@ func (mr *MockConfigerMockRecorder) Register() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockConfiger)(nil).Register))
 }
 
+// MockContextConfiger is a mock of ContextConfiger interface.
+type MockContextConfiger struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextConfigerMockRecorder
+}
+
+// MockContextConfigerMockRecorder is the mock recorder for MockContextConfiger.
+type MockContextConfigerMockRecorder struct {
+	mock *MockContextConfiger
+}
+
+// NewMockContextConfiger creates a new mock instance.
+func NewMockContextConfiger(ctrl *gomock.Controller) *MockContextConfiger {
+	mock := &MockContextConfiger{ctrl: ctrl}
+	mock.recorder = &MockContextConfigerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContextConfiger) EXPECT() *MockContextConfigerMockRecorder {
+	return m.recorder
+}
+
+// RegisterContext mocks base method.
+func (m *MockContextConfiger) RegisterContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterContext indicates an expected call of RegisterContext.
+func (mr *MockContextConfigerMockRecorder) RegisterContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterContext", reflect.TypeOf((*MockContextConfiger)(nil).RegisterContext), ctx)
+}
+
+// MockDefaulter is a mock of Defaulter interface.
+type MockDefaulter struct {
+	ctrl     *gomock.Controller
+	recorder *MockDefaulterMockRecorder
+}
+
+// MockDefaulterMockRecorder is the mock recorder for MockDefaulter.
+type MockDefaulterMockRecorder struct {
+	mock *MockDefaulter
+}
+
+// NewMockDefaulter creates a new mock instance.
+func NewMockDefaulter(ctrl *gomock.Controller) *MockDefaulter {
+	mock := &MockDefaulter{ctrl: ctrl}
+	mock.recorder = &MockDefaulterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDefaulter) EXPECT() *MockDefaulterMockRecorder {
+	return m.recorder
+}
+
+// SetDefaults mocks base method.
+func (m *MockDefaulter) SetDefaults() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaults")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaults indicates an expected call of SetDefaults.
+func (mr *MockDefaulterMockRecorder) SetDefaults() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaults", reflect.TypeOf((*MockDefaulter)(nil).SetDefaults))
+}
+
+// MockNormalizer is a mock of Normalizer interface.
+type MockNormalizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockNormalizerMockRecorder
+}
+
+// MockNormalizerMockRecorder is the mock recorder for MockNormalizer.
+type MockNormalizerMockRecorder struct {
+	mock *MockNormalizer
+}
+
+// NewMockNormalizer creates a new mock instance.
+func NewMockNormalizer(ctrl *gomock.Controller) *MockNormalizer {
+	mock := &MockNormalizer{ctrl: ctrl}
+	mock.recorder = &MockNormalizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNormalizer) EXPECT() *MockNormalizerMockRecorder {
+	return m.recorder
+}
+
+// Normalize mocks base method.
+func (m *MockNormalizer) Normalize() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Normalize")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Normalize indicates an expected call of Normalize.
+func (mr *MockNormalizerMockRecorder) Normalize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Normalize", reflect.TypeOf((*MockNormalizer)(nil).Normalize))
+}
+
 // MockValidater is a mock of Validater interface.
 type MockValidater struct {
 	ctrl     *gomock.Controller
@@ -84,6 +196,117 @@ func (mr *MockValidaterMockRecorder) Validate() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockValidater)(nil).Validate))
 }
 
+// MockContextValidater is a mock of ContextValidater interface.
+type MockContextValidater struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextValidaterMockRecorder
+}
+
+// MockContextValidaterMockRecorder is the mock recorder for MockContextValidater.
+type MockContextValidaterMockRecorder struct {
+	mock *MockContextValidater
+}
+
+// NewMockContextValidater creates a new mock instance.
+func NewMockContextValidater(ctrl *gomock.Controller) *MockContextValidater {
+	mock := &MockContextValidater{ctrl: ctrl}
+	mock.recorder = &MockContextValidaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContextValidater) EXPECT() *MockContextValidaterMockRecorder {
+	return m.recorder
+}
+
+// ValidateContext mocks base method.
+func (m *MockContextValidater) ValidateContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateContext indicates an expected call of ValidateContext.
+func (mr *MockContextValidaterMockRecorder) ValidateContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateContext", reflect.TypeOf((*MockContextValidater)(nil).ValidateContext), ctx)
+}
+
+// MockWarner is a mock of Warner interface.
+type MockWarner struct {
+	ctrl     *gomock.Controller
+	recorder *MockWarnerMockRecorder
+}
+
+// MockWarnerMockRecorder is the mock recorder for MockWarner.
+type MockWarnerMockRecorder struct {
+	mock *MockWarner
+}
+
+// NewMockWarner creates a new mock instance.
+func NewMockWarner(ctrl *gomock.Controller) *MockWarner {
+	mock := &MockWarner{ctrl: ctrl}
+	mock.recorder = &MockWarnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWarner) EXPECT() *MockWarnerMockRecorder {
+	return m.recorder
+}
+
+// Warn mocks base method.
+func (m *MockWarner) Warn() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Warn")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Warn indicates an expected call of Warn.
+func (mr *MockWarnerMockRecorder) Warn() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockWarner)(nil).Warn))
+}
+
+// MockFinalizer is a mock of Finalizer interface.
+type MockFinalizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockFinalizerMockRecorder
+}
+
+// MockFinalizerMockRecorder is the mock recorder for MockFinalizer.
+type MockFinalizerMockRecorder struct {
+	mock *MockFinalizer
+}
+
+// NewMockFinalizer creates a new mock instance.
+func NewMockFinalizer(ctrl *gomock.Controller) *MockFinalizer {
+	mock := &MockFinalizer{ctrl: ctrl}
+	mock.recorder = &MockFinalizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFinalizer) EXPECT() *MockFinalizerMockRecorder {
+	return m.recorder
+}
+
+// Finalize mocks base method.
+func (m *MockFinalizer) Finalize() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finalize")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finalize indicates an expected call of Finalize.
+func (mr *MockFinalizerMockRecorder) Finalize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finalize", reflect.TypeOf((*MockFinalizer)(nil).Finalize))
+}
+
 // MockPrinter is a mock of Printer interface.
 type MockPrinter struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,181 @@
+package goconf
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// KeyNaming defines how configuration field names are rendered in the output
+type KeyNaming string
+
+const (
+	// KeyNamingField uses the Go field path, e.g. Database.Host (default)
+	KeyNamingField KeyNaming = "field"
+	// KeyNamingEnv uses the environment variable name, e.g. DATABASE_HOST
+	KeyNamingEnv KeyNaming = "env"
+	// KeyNamingYAML uses the YAML key path, e.g. database.host
+	KeyNamingYAML KeyNaming = "yaml"
+	// KeyNamingJSON uses the json tag names, e.g. database.host
+	KeyNamingJSON KeyNaming = "json"
+)
+
+var currentKeyNaming = KeyNamingField
+
+// SetKeyNaming sets how field names are rendered when printing configuration.
+// Fields without the corresponding tag fall back to their Go field name.
+func SetKeyNaming(naming KeyNaming) {
+	currentKeyNaming = naming
+}
+
+// tagName returns the name part of a struct tag such as `yaml:"name,omitempty"`.
+// An empty string is returned if the tag is missing or set to "-".
+func tagName(tag reflect.StructTag, key string) string {
+	value, ok := tag.Lookup(key)
+	if !ok {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(value, ",")
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+// keyName returns the name of a single path segment for the given naming mode
+func keyName(naming KeyNaming, field reflect.StructField) string {
+	var name string
+
+	switch naming {
+	case KeyNamingYAML:
+		name = tagName(field.Tag, "yaml")
+	case KeyNamingJSON:
+		name = tagName(field.Tag, "json")
+	}
+
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+// EnvNaming configures how ParseEnv reads fields without an env tag
+type EnvNaming struct {
+	// Derive reads fields without an env tag from a variable named after their
+	// field path in UPPER_SNAKE_CASE, e.g. DATABASE_HOST for Database.Host.
+	// The envPrefix tag of a nested struct replaces its name in the path.
+	Derive bool
+	// Prefix is prepended to the derived names, e.g. APP_ for APP_DATABASE_HOST
+	Prefix string
+}
+
+var currentEnvNaming EnvNaming
+
+// SetEnvNaming sets how fields without an env tag are read from the environment.
+// Fields with an env tag keep their name. The derived names are also used when
+// printing and checking configuration.
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    Port     int // APP_PORT
+//	    Database struct {
+//	        Host string // APP_DATABASE_HOST
+//	    }
+//	    Cache struct {
+//	        Host string // APP_REDIS_HOST
+//	    } `envPrefix:"REDIS_"`
+//	}
+//
+//	goconf.SetEnvNaming(goconf.EnvNaming{Derive: true, Prefix: "APP_"})
+func SetEnvNaming(naming EnvNaming) {
+	currentEnvNaming = naming
+}
+
+// CurrentEnvNaming returns the EnvNaming set with SetEnvNaming
+func CurrentEnvNaming() EnvNaming {
+	return currentEnvNaming
+}
+
+// EnvPath resolves environment variable names like ParseEnv, for tools that list
+// the variables of a configuration. It holds the environment variable prefixes of
+// a struct: the envPrefix tags of its parent structs, prepended to env tags, and
+// the prefix of the names derived with SetEnvNaming.
+//
+// Usage Example:
+//
+//	path := goconf.RootEnvPath()
+//	for _, field := range reflect.VisibleFields(reflect.TypeOf(Config{})) {
+//	    if name, ok := path.Key(field); ok {
+//	        fmt.Println(name)
+//	    }
+//	}
+type EnvPath struct {
+	prefix  string
+	derived string
+}
+
+// RootEnvPath returns the EnvPath of a top level configuration struct
+func RootEnvPath() EnvPath {
+	return EnvPath{derived: currentEnvNaming.Prefix}
+}
+
+// Key returns the environment variable name of a field. The second return value
+// reports whether the field has one, i.e. an env tag or a derived name.
+func (p EnvPath) Key(field reflect.StructField) (string, bool) {
+	if name := tagName(field.Tag, "env"); name != "" {
+		return p.prefix + name, true
+	}
+
+	if !currentEnvNaming.Derive || isEnvIgnored(field) {
+		return "", false
+	}
+
+	return p.derived + EnvName(field.Name), true
+}
+
+// Nested returns the EnvPath of the fields of a nested struct field
+func (p EnvPath) Nested(field reflect.StructField) EnvPath {
+	prefix := field.Tag.Get("envPrefix")
+
+	nested := EnvPath{prefix: p.prefix + prefix, derived: p.derived + prefix}
+	if prefix == "" && !field.Anonymous {
+		nested.derived += EnvName(field.Name) + "_"
+	}
+
+	return nested
+}
+
+// isEnvIgnored reports whether a field is excluded from the environment with `env:"-"`
+func isEnvIgnored(field reflect.StructField) bool {
+	name, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+
+	return name == "-"
+}
+
+// EnvName converts a Go field name to the UPPER_SNAKE_CASE segment of its derived
+// environment variable name, see SetEnvNaming. For example MaxIdleConns becomes
+// MAX_IDLE_CONNS, HTTPPort HTTP_PORT and PortA PORT_A. A word starts at an upper
+// case letter following a lower case letter, or followed by one.
+func EnvName(name string) string {
+	var b strings.Builder
+
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' {
+			continue
+		}
+
+		if b.Len() > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteRune('_')
+		}
+
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
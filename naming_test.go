@@ -0,0 +1,255 @@
+package goconf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namingConfig struct {
+	DatabaseURL string `env:"DATABASE_URL" yaml:"database_url" json:"databaseUrl"`
+	Debug       bool
+	Cache       struct {
+		Host     string `env:"HOST" yaml:"host" json:"host"`
+		Password string `env:"PASSWORD,required" yaml:"password" json:"password" secret:"true"`
+	} `yaml:"cache" json:"cache" envPrefix:"CACHE_"`
+}
+
+func newNamingConfig() namingConfig {
+	cfg := namingConfig{DatabaseURL: "postgres://localhost", Debug: true}
+	cfg.Cache.Host = "redis"
+	cfg.Cache.Password = "redis-secret"
+
+	return cfg
+}
+
+func TestTableRowsKeyNaming(t *testing.T) {
+	tests := map[string]struct {
+		naming   KeyNaming
+		expected [][]string
+	}{
+		"field path": {
+			naming: KeyNamingField,
+			expected: [][]string{
+				{"DatabaseURL", "postgres://localhost"},
+				{"Debug", "true"},
+				{"Cache.Host", "redis"},
+				{"Cache.Password", SensitiveDataMaskString},
+			},
+		},
+		"env var name": {
+			naming: KeyNamingEnv,
+			expected: [][]string{
+				{"DATABASE_URL", "postgres://localhost"},
+				{"Debug", "true"},
+				{"CACHE_HOST", "redis"},
+				{"CACHE_PASSWORD", SensitiveDataMaskString},
+			},
+		},
+		"yaml key path": {
+			naming: KeyNamingYAML,
+			expected: [][]string{
+				{"database_url", "postgres://localhost"},
+				{"Debug", "true"},
+				{"cache.host", "redis"},
+				{"cache.password", SensitiveDataMaskString},
+			},
+		},
+		"json tag": {
+			naming: KeyNamingJSON,
+			expected: [][]string{
+				{"databaseUrl", "postgres://localhost"},
+				{"Debug", "true"},
+				{"cache.host", "redis"},
+				{"cache.password", SensitiveDataMaskString},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rows := tableRows(buildFields(test.naming, reflect.ValueOf(newNamingConfig())))
+			assert.Equal(t, test.expected, rows)
+		})
+	}
+}
+
+func TestJSONMapKeyNaming(t *testing.T) {
+	tests := map[string]struct {
+		naming   KeyNaming
+		expected map[string]interface{}
+	}{
+		"field path": {
+			naming: KeyNamingField,
+			expected: map[string]interface{}{
+				"DatabaseURL": "postgres://localhost",
+				"Debug":       true,
+				"Cache": map[string]interface{}{
+					"Host":     "redis",
+					"Password": SensitiveDataMaskString,
+				},
+			},
+		},
+		"env var name is flat": {
+			naming: KeyNamingEnv,
+			expected: map[string]interface{}{
+				"DATABASE_URL":   "postgres://localhost",
+				"Debug":          true,
+				"CACHE_HOST":     "redis",
+				"CACHE_PASSWORD": SensitiveDataMaskString,
+			},
+		},
+		"yaml key path": {
+			naming: KeyNamingYAML,
+			expected: map[string]interface{}{
+				"database_url": "postgres://localhost",
+				"Debug":        true,
+				"cache": map[string]interface{}{
+					"host":     "redis",
+					"password": SensitiveDataMaskString,
+				},
+			},
+		},
+		"json tag": {
+			naming: KeyNamingJSON,
+			expected: map[string]interface{}{
+				"databaseUrl": "postgres://localhost",
+				"Debug":       true,
+				"cache": map[string]interface{}{
+					"host":     "redis",
+					"password": SensitiveDataMaskString,
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configMap := jsonMap(test.naming, buildFields(test.naming, reflect.ValueOf(newNamingConfig())))
+			assert.Equal(t, test.expected, configMap)
+		})
+	}
+}
+
+func TestSetKeyNaming(t *testing.T) {
+	original := currentKeyNaming
+	defer func() { currentKeyNaming = original }()
+
+	SetKeyNaming(KeyNamingEnv)
+
+	var buf bytes.Buffer
+	err := printConfig(&buf, printerMock(gomock.NewController(t), newNamingConfig()).(Printer))
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "CACHE_HOST")
+	assert.Contains(t, output, "CACHE_PASSWORD")
+	assert.NotContains(t, output, "redis-secret")
+}
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"Port":         "PORT",
+		"MaxIdleConns": "MAX_IDLE_CONNS",
+		"HTTPPort":     "HTTP_PORT",
+		"DatabaseURL":  "DATABASE_URL",
+		"TLS":          "TLS",
+		"Snake_Case":   "SNAKE_CASE",
+		"V2Endpoint":   "V2_ENDPOINT",
+		"PortA":        "PORT_A",
+		"ReplicaSetB":  "REPLICA_SET_B",
+	}
+
+	for name, expected := range tests {
+		assert.Equal(t, expected, EnvName(name), name)
+	}
+}
+
+func TestSetEnvNamingKeys(t *testing.T) {
+	defer SetEnvNaming(EnvNaming{})
+
+	type config struct {
+		Port     int
+		Ignored  string `env:"-"`
+		Database struct {
+			Host string
+			User string `env:"DB_USER"`
+		}
+		Cache struct {
+			Host string
+		} `envPrefix:"REDIS_"`
+		Embedded
+	}
+
+	var sources []string
+	for _, f := range FlattenFields(buildFields(KeyNamingEnv, reflect.ValueOf(config{}))) {
+		sources = append(sources, f.Source)
+	}
+	assert.Equal(t, []string{"", "", "", "DB_USER", "", "", ""}, sources)
+
+	SetEnvNaming(EnvNaming{Derive: true, Prefix: "APP_"})
+	assert.Equal(t, EnvNaming{Derive: true, Prefix: "APP_"}, CurrentEnvNaming())
+
+	var keys []string
+	for _, f := range FlattenFields(buildFields(KeyNamingEnv, reflect.ValueOf(config{}))) {
+		keys = append(keys, f.Key)
+	}
+	assert.Equal(t, []string{"APP_PORT", "Ignored", "APP_DATABASE_HOST", "DB_USER", "APP_REDIS_HOST", "APP_LEVEL", "APP_TIMEOUT"}, keys)
+}
+
+// Embedded is embedded in a config to test derived env names
+type Embedded struct {
+	Level   string
+	Timeout time.Duration
+}
+
+func TestEnvPath(t *testing.T) {
+	defer SetEnvNaming(EnvNaming{})
+
+	type config struct {
+		Port     int `env:"PORT"`
+		Ignored  int `env:"-"`
+		Database struct {
+			Host string
+			User string `env:"USER"`
+		} `envPrefix:"DB_"`
+		Cache struct {
+			Host string
+		}
+	}
+
+	fields := func(t reflect.Type) map[string]reflect.StructField {
+		byName := map[string]reflect.StructField{}
+		for _, f := range reflect.VisibleFields(t) {
+			byName[f.Name] = f
+		}
+
+		return byName
+	}
+
+	key := func(path EnvPath, field reflect.StructField) string {
+		name, _ := path.Key(field)
+		return name
+	}
+
+	root := fields(reflect.TypeOf(config{}))
+	database := fields(root["Database"].Type)
+	cache := fields(root["Cache"].Type)
+
+	name, ok := RootEnvPath().Key(root["Ignored"])
+	assert.False(t, ok)
+	assert.Empty(t, name)
+	assert.Equal(t, "PORT", key(RootEnvPath(), root["Port"]))
+	assert.Equal(t, "DB_USER", key(RootEnvPath().Nested(root["Database"]), database["User"]))
+	assert.Empty(t, key(RootEnvPath().Nested(root["Database"]), database["Host"]))
+
+	SetEnvNaming(EnvNaming{Derive: true, Prefix: "APP_"})
+	assert.Equal(t, "APP_DB_HOST", key(RootEnvPath().Nested(root["Database"]), database["Host"]))
+	assert.Equal(t, "APP_CACHE_HOST", key(RootEnvPath().Nested(root["Cache"]), cache["Host"]))
+	assert.Equal(t, "DB_USER", key(RootEnvPath().Nested(root["Database"]), database["User"]))
+}
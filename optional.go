@@ -0,0 +1,215 @@
+package goconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnsetString is printed in place of the value of an Optional field without a value
+const UnsetString = "<unset>"
+
+// Optional holds a configuration value together with whether it was set, so that
+// a value explicitly set to zero can be told apart from a value that was not
+// configured at all.
+//
+// ParseEnv sets an Optional if its environment variable is present and ParseYaml
+// if its key is present with a non-null value. For validation an Optional behaves
+// like a pointer: `required` means the value is present, and `omitempty` skips the
+// remaining rules if it is absent.
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    Timeout goconf.Optional[time.Duration] `env:"TIMEOUT" yaml:"timeout" validate:"omitempty,gte=0"`
+//	}
+//
+//	if timeout, ok := cfg.Timeout.Get(); ok {
+//	    client.Timeout = timeout // 0 disables the timeout
+//	}
+type Optional[T any] struct {
+	value T
+	set   bool
+}
+
+// Some returns an Optional holding value
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, set: true}
+}
+
+// Get returns the value and whether it is set
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+// IsSet reports whether the value is set
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// Value returns the value, or the zero value of T if it is not set
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// Or returns the value if it is set, otherwise fallback
+func (o Optional[T]) Or(fallback T) T {
+	if !o.set {
+		return fallback
+	}
+
+	return o.value
+}
+
+// Set sets the value
+func (o *Optional[T]) Set(value T) {
+	o.value = value
+	o.set = true
+}
+
+// Unset clears the value
+func (o *Optional[T]) Unset() {
+	var zero T
+	o.value = zero
+	o.set = false
+}
+
+// String returns the formatted value, or UnsetString if the value is not set
+func (o Optional[T]) String() string {
+	if !o.set {
+		return UnsetString
+	}
+
+	return formatValue(reflect.ValueOf(&o.value).Elem())
+}
+
+// UnmarshalText sets the value from text using the same rules as the env parser
+func (o *Optional[T]) UnmarshalText(text []byte) error {
+	var value T
+	if err := setFromString(reflect.ValueOf(&value).Elem(), string(text)); err != nil {
+		return err
+	}
+
+	o.Set(value)
+
+	return nil
+}
+
+// UnmarshalYAML sets the value from a YAML node, a null node clears the value
+func (o *Optional[T]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Tag == "!!null" {
+		o.Unset()
+		return nil
+	}
+
+	var value T
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+
+	o.Set(value)
+
+	return nil
+}
+
+// MarshalYAML encodes the value, or null if it is not set
+func (o Optional[T]) MarshalYAML() (interface{}, error) {
+	if !o.set {
+		return nil, nil
+	}
+
+	return o.value, nil
+}
+
+// UnmarshalJSON sets the value from JSON, null clears the value
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		o.Unset()
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	o.Set(value)
+
+	return nil
+}
+
+// MarshalJSON encodes the value, or null if it is not set
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.set {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.value)
+}
+
+// optionalValue returns a pointer to a copy of the value, or a nil pointer if
+// the value is not set, which gives Optional the validation semantics of a pointer
+func (o Optional[T]) optionalValue() interface{} {
+	if !o.set {
+		return (*T)(nil)
+	}
+
+	value := o.value
+
+	return &value
+}
+
+// optional is implemented by every Optional type
+type optional interface {
+	optionalValue() interface{}
+	fmt.Stringer
+}
+
+var optionalType = reflect.TypeOf((*optional)(nil)).Elem()
+
+// isOptionalType reports whether t is an Optional type
+func isOptionalType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Implements(optionalType)
+}
+
+// optionalTypes returns the Optional types used anywhere within t
+func optionalTypes(t reflect.Type) []reflect.Type {
+	var types []reflect.Type
+	seen := make(map[reflect.Type]bool)
+
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+
+		if isOptionalType(t) {
+			types = append(types, t)
+			return
+		}
+
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			walk(t.Elem())
+		case reflect.Map:
+			walk(t.Key())
+			walk(t.Elem())
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				walk(t.Field(i).Type)
+			}
+		}
+	}
+
+	walk(t)
+
+	return types
+}
+
+// optionalValidationValue is the validator custom type function of Optional types
+func optionalValidationValue(field reflect.Value) interface{} {
+	return field.Interface().(optional).optionalValue()
+}
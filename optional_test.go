@@ -0,0 +1,147 @@
+package goconf
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type optionalConfig struct {
+	Timeout Optional[time.Duration] `env:"OPTIONAL_TIMEOUT" yaml:"timeout" validate:"omitempty,gte=0"`
+	Retries Optional[int]           `env:"OPTIONAL_RETRIES" yaml:"retries" validate:"required"`
+	Hosts   Optional[[]string]      `env:"OPTIONAL_HOSTS" yaml:"hosts"`
+}
+
+func TestOptional(t *testing.T) {
+	var o Optional[int]
+	value, ok := o.Get()
+	assert.False(t, ok)
+	assert.Equal(t, 0, value)
+	assert.Equal(t, 5, o.Or(5))
+	assert.Equal(t, UnsetString, o.String())
+
+	o.Set(0)
+	assert.True(t, o.IsSet())
+	assert.Equal(t, 0, o.Or(5))
+	assert.Equal(t, "0", o.String())
+
+	o.Unset()
+	assert.False(t, o.IsSet())
+
+	assert.Equal(t, Some("x"), func() Optional[string] {
+		var s Optional[string]
+		require.NoError(t, s.UnmarshalText([]byte("x")))
+		return s
+	}())
+}
+
+func TestOptionalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Set   Optional[int]
+		Unset Optional[int]
+	}{Set: Some(0)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Set": 0, "Unset": null}`, string(data))
+
+	var o Optional[int]
+	require.NoError(t, json.Unmarshal([]byte("0"), &o))
+	assert.Equal(t, Some(0), o)
+
+	require.NoError(t, json.Unmarshal([]byte("null"), &o))
+	assert.False(t, o.IsSet())
+}
+
+func TestOptionalParseEnv(t *testing.T) {
+	t.Setenv("OPTIONAL_TIMEOUT", "0s")
+	t.Setenv("OPTIONAL_HOSTS", "a,b")
+	_ = os.Unsetenv("OPTIONAL_RETRIES")
+
+	var cfg optionalConfig
+	require.NoError(t, ParseEnv(&cfg))
+
+	assert.Equal(t, Some(time.Duration(0)), cfg.Timeout)
+	assert.False(t, cfg.Retries.IsSet())
+	assert.Equal(t, Some([]string{"a", "b"}), cfg.Hosts)
+}
+
+func TestOptionalParseYaml(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("timeout: 0s\nretries: 0\nhosts: null\n"), 0o600))
+
+	var cfg optionalConfig
+	require.NoError(t, ParseYaml(&cfg, filePath))
+
+	assert.Equal(t, Some(time.Duration(0)), cfg.Timeout)
+	assert.Equal(t, Some(0), cfg.Retries)
+	assert.False(t, cfg.Hosts.IsSet())
+
+	data, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "timeout: 0s\nretries: 0\nhosts: null\n", string(data))
+}
+
+func TestOptionalValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      optionalConfig
+		expectedErr string
+	}{
+		{
+			name:   "present zero value satisfies required",
+			config: optionalConfig{Retries: Some(0)},
+		},
+		{
+			name:        "absent value fails required",
+			config:      optionalConfig{},
+			expectedErr: "Retries",
+		},
+		{
+			name:        "present value is validated",
+			config:      optionalConfig{Timeout: Some(-time.Second), Retries: Some(1)},
+			expectedErr: "Timeout",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewValidator().Struct(test.config)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErrors validator.ValidationErrors
+			require.ErrorAs(t, err, &validationErrors)
+			assert.Equal(t, test.expectedErr, validationErrors[0].Field())
+		})
+	}
+
+	assert.NoError(t, NewValidator().Var(Some(3), "required,gte=3"))
+	assert.Error(t, NewValidator().Var(Optional[int]{}, "required"))
+}
+
+func TestOptionalFields(t *testing.T) {
+	fields := buildFields(KeyNamingField, reflect.ValueOf(optionalConfig{Retries: Some(3)}))
+
+	require.Len(t, fields, 3)
+	assert.Equal(t, UnsetString, fields[0].Value)
+	assert.True(t, fields[0].Unset)
+	assert.Nil(t, fields[0].Fields)
+	assert.Equal(t, 3, fields[1].Value)
+	assert.False(t, fields[1].Unset)
+
+	assert.Equal(t, [][]string{{"Timeout", "<unset>"}, {"Retries", "3"}, {"Hosts", "<unset>"}}, tableRows(fields))
+
+	var b bytes.Buffer
+	require.NoError(t, renderYAML(&b, fields))
+	assert.Equal(t, "retries: 3\n", b.String())
+}
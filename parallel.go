@@ -0,0 +1,96 @@
+package goconf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var currentLoadConcurrency = 1
+
+// SetLoadConcurrency sets how many configurations Load registers and validates
+// at the same time. The default of 1 loads configurations one after another and
+// stops at the first error. A value of 0 or less loads all independent
+// configurations at once.
+//
+// With concurrent loading a configuration implementing Depender starts once all
+// of its dependencies are loaded, and is skipped if any of them failed. Errors of
+// all configurations are joined in load order, each unchanged as with serial loading. Cross validation, finalization and
+// printing still run one configuration at a time in load order, so the output does
+// not depend on the concurrency.
+func SetLoadConcurrency(n int) {
+	currentLoadConcurrency = n
+}
+
+// loadConcurrently calls loadConfig for every configuration, running at most
+// limit calls at the same time. ordered must be sorted by orderConfigs.
+func loadConcurrently(ctx context.Context, ordered []Configer, limit int) error {
+	deps, err := dependencyIndexes(ordered)
+	if err != nil {
+		return err
+	}
+
+	if limit < 1 || limit > len(ordered) {
+		limit = len(ordered)
+	}
+
+	sem := make(chan struct{}, limit)
+	done := make([]chan struct{}, len(ordered))
+	errs := make([]error, len(ordered))
+	failed := make([]bool, len(ordered))
+
+	for i := range ordered {
+		done[i] = make(chan struct{})
+	}
+
+	for i, c := range ordered {
+		go func() {
+			defer close(done[i])
+
+			for _, j := range deps[i] {
+				<-done[j]
+				if failed[j] {
+					failed[i] = true
+					return
+				}
+			}
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if err := loadConfig(ctx, c); err != nil {
+				failed[i] = true
+				errs[i] = err
+			}
+		}()
+	}
+
+	for i := range ordered {
+		<-done[i]
+	}
+
+	return errors.Join(errs...)
+}
+
+// dependencyIndexes returns the indexes of the dependencies of every configuration
+func dependencyIndexes(configs []Configer) ([][]int, error) {
+	deps := make([][]int, len(configs))
+
+	for i, c := range configs {
+		d, ok := c.(Depender)
+		if !ok {
+			continue
+		}
+
+		for _, dep := range d.DependsOn() {
+			j, err := findConfig(configs, dep)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", configName(c), err)
+			}
+
+			deps[i] = append(deps[i], j)
+		}
+	}
+
+	return deps, nil
+}
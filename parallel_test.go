@@ -0,0 +1,155 @@
+package goconf
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wgarunap/goconf/mocks"
+)
+
+// dependentConfig is a Configer that depends on other configurations
+type dependentConfig struct {
+	*mocks.MockConfiger
+	deps []Configer
+}
+
+func (c *dependentConfig) DependsOn() []Configer {
+	return c.deps
+}
+
+func TestLoadConcurrently(t *testing.T) {
+	originalConcurrency := currentLoadConcurrency
+	defer func() { currentLoadConcurrency = originalConcurrency }()
+
+	t.Run("independent configurations register concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		SetLoadConcurrency(0)
+
+		// Each Register waits for the other, which only finishes if both run at once
+		var started sync.WaitGroup
+		started.Add(2)
+		register := func() error {
+			started.Done()
+			started.Wait()
+			return nil
+		}
+
+		first := mocks.NewMockConfiger(ctrl)
+		first.EXPECT().Register().DoAndReturn(register)
+		second := mocks.NewMockConfiger(ctrl)
+		second.EXPECT().Register().DoAndReturn(register)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		assert.NoError(t, LoadContext(ctx, first, second))
+	})
+
+	t.Run("dependencies are loaded first", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		SetLoadConcurrency(0)
+
+		var mu sync.Mutex
+		var calls []string
+		record := func(name string) func() error {
+			return func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				calls = append(calls, name)
+				return nil
+			}
+		}
+
+		base := mocks.NewMockConfiger(ctrl)
+		base.EXPECT().Register().DoAndReturn(func() error {
+			time.Sleep(10 * time.Millisecond)
+			return record("base")()
+		})
+		dependent := &dependentConfig{MockConfiger: mocks.NewMockConfiger(ctrl), deps: []Configer{base}}
+		dependent.EXPECT().Register().DoAndReturn(record("dependent"))
+
+		assert.NoError(t, Load(dependent, base))
+		assert.Equal(t, []string{"base", "dependent"}, calls)
+	})
+
+	t.Run("errors are joined in load order", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		SetLoadConcurrency(2)
+
+		failing := mocks.NewMockConfiger(ctrl)
+		failing.EXPECT().Register().Return(errors.New("register failed"))
+		invalid := mock(ctrl, nil, errors.New("validate failed"))
+		skipped := &dependentConfig{MockConfiger: mocks.NewMockConfiger(ctrl), deps: []Configer{failing}}
+
+		err := Load(failing, skipped, invalid)
+		assert.EqualError(t, err, "register failed\nvalidate failed")
+	})
+
+	t.Run("errors have the same shape as with serial loading", func(t *testing.T) {
+		t.Setenv("LOADER_PORT", "80")
+		loader := NewLoader(WithValidator(newLoaderValidator(t)))
+
+		SetLoadConcurrency(1)
+		serial := loader.Load(&loaderConfig{})
+		require.Error(t, serial)
+
+		SetLoadConcurrency(2)
+		concurrent := loader.Load(&loaderConfig{})
+		assert.Equal(t, serial.Error(), concurrent.Error())
+		assert.Equal(t, loader.FormatError(serial), loader.FormatError(concurrent))
+	})
+
+	t.Run("output order does not depend on concurrency", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		SetLoadConcurrency(0)
+
+		printer := func(name string, delay time.Duration) Configer {
+			return printerMockWithDelay(ctrl, struct{ Name string }{Name: name}, delay)
+		}
+
+		output := captureStdout(t, func() error {
+			return Load(printer("first", 20*time.Millisecond), printer("second", 0))
+		})
+
+		assert.Less(t, strings.Index(output, "first"), strings.Index(output, "second"))
+	})
+}
+
+func TestDependencyIndexes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	base := mocks.NewMockConfiger(ctrl)
+	dependent := &dependentConfig{MockConfiger: mocks.NewMockConfiger(ctrl), deps: []Configer{base}}
+
+	deps, err := dependencyIndexes([]Configer{base, dependent})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{nil, {0}}, deps)
+
+	_, err = dependencyIndexes([]Configer{dependent})
+	assert.EqualError(t, err, "dependentConfig: depends on MockConfiger which is not loaded")
+}
+
+// printerMockWithDelay returns a Configer whose Register returns after delay
+func printerMockWithDelay(ctrl *gomock.Controller, config interface{}, delay time.Duration) Configer {
+	mockConfiger := mocks.NewMockConfiger(ctrl)
+	mockPrinter := mocks.NewMockPrinter(ctrl)
+
+	mockConfiger.EXPECT().Register().DoAndReturn(func() error {
+		time.Sleep(delay)
+		return nil
+	})
+	mockPrinter.EXPECT().Print().Return(config).AnyTimes()
+
+	return &struct {
+		*mocks.MockConfiger
+		*mocks.MockPrinter
+	}{mockConfiger, mockPrinter}
+}
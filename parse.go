@@ -1,7 +1,16 @@
 // Package goconf provides utilities for loading and validating environment-based configuration in Go applications.
 package goconf
 
-import "github.com/caarlos0/env/v11"
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/caarlos0/env/v11"
+)
+
+var urlType = reflect.TypeOf(url.URL{})
 
 // ParseEnv parse the env values to given struct fields
 // env variables are defined using struct tags. It utilizes the "github.com/caarlos0/env/v11"
@@ -29,8 +38,95 @@ import "github.com/caarlos0/env/v11"
 //
 // Note:
 //   - The function will panic if the `config` parameter is not a pointer to struct
+//   - Values of fields tagged with `deprecated` and `replacedBy` are copied into the
+//     replacement field if their env variable is set and the one of the replacement is not
+//   - The `default` tag is not applied, Load applies it before Register and
+//     ApplyDefaults can be called before ParseEnv when used on its own
+//   - Fields without an env tag are read from derived variable names if enabled with
+//     SetEnvNaming
 //
 // More env package information https://github.com/caarlos0/env/v11
 func ParseEnv(config interface{}) error {
-	return env.Parse(config)
+	if err := env.Parse(config); err != nil {
+		return err
+	}
+
+	if currentEnvNaming.Derive {
+		if err := parseDerivedEnv(structValue(config), RootEnvPath()); err != nil {
+			return err
+		}
+	}
+
+	return applyReplacements(config, envSource{path: RootEnvPath()})
+}
+
+// parseDerivedEnv reads the fields without an env tag from their derived variable
+// names, returning the errors of all fields like the env parser
+func parseDerivedEnv(values reflect.Value, path EnvPath) error {
+	var errs env.AggregateError
+
+	for i := 0; i < values.NumField(); i++ {
+		field := values.Field(i)
+		structField := values.Type().Field(i)
+
+		if !structField.IsExported() || isEnvIgnored(structField) {
+			continue
+		}
+
+		if field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Struct && !isEnvLeaf(field.Elem().Type()) {
+			field = field.Elem()
+		}
+
+		var err error
+		switch {
+		case field.Kind() == reflect.Struct && !isEnvLeaf(field.Type()):
+			err = parseDerivedEnv(field, path.Nested(structField))
+		case tagName(structField.Tag, "env") == "":
+			key, _ := path.Key(structField)
+			err = parseDerivedField(field, structField, key)
+		}
+
+		if aggregate, ok := err.(env.AggregateError); ok {
+			errs.Errors = append(errs.Errors, aggregate.Errors...)
+		} else if err != nil {
+			errs.Errors = append(errs.Errors, err)
+		}
+	}
+
+	if len(errs.Errors) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// parseDerivedField parses a field from the variable key with the env parser,
+// keeping the options of its env tag and its envDefault and separators
+func parseDerivedField(value reflect.Value, field reflect.StructField, key string) error {
+	_, options, _ := strings.Cut(field.Tag.Get("env"), ",")
+
+	tag := fmt.Sprintf("env:%q", strings.TrimSuffix(key+","+options, ","))
+	for _, name := range []string{"envDefault", "envSeparator", "envKeyValSeparator"} {
+		if v, ok := field.Tag.Lookup(name); ok {
+			tag += fmt.Sprintf(" %s:%q", name, v)
+		}
+	}
+
+	holder := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: field.Name, Type: field.Type, Tag: reflect.StructTag(tag)},
+	}))
+	holder.Elem().Field(0).Set(value)
+
+	if err := env.Parse(holder.Interface()); err != nil {
+		return err
+	}
+
+	value.Set(holder.Elem().Field(0))
+
+	return nil
+}
+
+// isEnvLeaf reports whether the env parser reads a struct type from a single variable
+func isEnvLeaf(t reflect.Type) bool {
+	return t == urlType || t == locationType || reflect.PointerTo(t).Implements(textUnmarshalerType)
 }
@@ -3,6 +3,7 @@ package goconf
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -59,3 +60,56 @@ func updateEnv() {
 	_ = os.Setenv("MY_AGE", "99")
 	_ = os.Setenv("MY_TEAM", "backend")
 }
+
+func TestParseEnvDerivedNames(t *testing.T) {
+	defer SetEnvNaming(EnvNaming{})
+	SetEnvNaming(EnvNaming{Derive: true, Prefix: "APP_"})
+
+	type config struct {
+		Port     int `default:"8080"`
+		Name     string
+		Hosts    []string `envSeparator:";"`
+		Region   string   `envDefault:"eu-west-1"`
+		Tagged   string   `env:"TAGGED"`
+		Ignored  string   `env:"-"`
+		Database struct {
+			Host     string
+			Password string `env:",required"`
+		}
+		Cache *struct {
+			Host string
+		} `envPrefix:"REDIS_"`
+		Created time.Time
+	}
+
+	t.Setenv("APP_NAME", "app")
+	t.Setenv("APP_HOSTS", "a;b")
+	t.Setenv("TAGGED", "tagged")
+	t.Setenv("APP_IGNORED", "ignored")
+	t.Setenv("APP_DATABASE_HOST", "db")
+	t.Setenv("APP_DATABASE_PASSWORD", "secret")
+	t.Setenv("APP_REDIS_HOST", "redis")
+	t.Setenv("APP_CREATED", "2024-01-02T03:04:05Z")
+
+	cfg := config{Cache: &struct {
+		Host string
+	}{}}
+	require.NoError(t, ApplyDefaults(&cfg))
+	require.NoError(t, ParseEnv(&cfg))
+
+	assert.Equal(t, 8080, cfg.Port)
+	assert.Equal(t, "app", cfg.Name)
+	assert.Equal(t, []string{"a", "b"}, cfg.Hosts)
+	assert.Equal(t, "eu-west-1", cfg.Region)
+	assert.Equal(t, "tagged", cfg.Tagged)
+	assert.Empty(t, cfg.Ignored)
+	assert.Equal(t, "db", cfg.Database.Host)
+	assert.Equal(t, "secret", cfg.Database.Password)
+	assert.Equal(t, "redis", cfg.Cache.Host)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), cfg.Created)
+
+	t.Setenv("APP_PORT", "http")
+	require.NoError(t, os.Unsetenv("APP_DATABASE_PASSWORD"))
+	require.EqualError(t, ParseEnv(&config{}), `env: parse error on field "Port" of type "int": strconv.ParseInt: parsing "http": invalid syntax; `+
+		`required environment variable "APP_DATABASE_PASSWORD" is not set`)
+}
@@ -3,14 +3,7 @@
 package goconf
 
 import (
-	"encoding/json"
-	"fmt"
-	"log"
-	"os"
-	"reflect"
-	"strconv"
-
-	"github.com/olekukonko/tablewriter"
+	"context"
 )
 
 // SensitiveDataMaskString is the default mask used to hide sensitive configuration values
@@ -24,11 +17,20 @@ const (
 	OutputFormatTable OutputFormat = "table"
 	// OutputFormatJSON outputs configuration as JSON
 	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatYAML outputs configuration as YAML that can be loaded with ParseYaml
+	OutputFormatYAML OutputFormat = "yaml"
+	// OutputFormatDotenv outputs configuration as KEY=value lines using env tag names
+	OutputFormatDotenv OutputFormat = "dotenv"
+	// OutputFormatLogfmt outputs configuration as a single logfmt line
+	OutputFormatLogfmt OutputFormat = "logfmt"
+	// OutputFormatMarkdown outputs configuration as a markdown table
+	OutputFormatMarkdown OutputFormat = "markdown"
 )
 
 var currentOutputFormat = OutputFormatTable
 
-// SetOutputFormat sets the output format for configuration printing
+// SetOutputFormat sets the output format for configuration printing.
+// Formats without a registered Renderer fall back to the table output.
 func SetOutputFormat(format OutputFormat) {
 	currentOutputFormat = format
 }
@@ -39,172 +41,166 @@ type Configer interface {
 	Register() error
 }
 
+// ContextConfiger interface can be implemented instead of Configer.Register to
+// register the configuration with a context, e.g. to bound a slow file or network read.
+// LoadContext calls RegisterContext in place of Register.
+type ContextConfiger interface {
+	RegisterContext(ctx context.Context) error
+}
+
+// Defaulter interface can be implemented to set default values before Register
+// reads the configuration sources
+type Defaulter interface {
+	SetDefaults() error
+}
+
+// Normalizer interface can be implemented to normalize the configuration or
+// compute derived fields after Register and before Validate, e.g. to lowercase
+// a log level or to expand ~ in a path
+type Normalizer interface {
+	Normalize() error
+}
+
 // Validater interface can be implemented to enable configuration validation
 type Validater interface {
 	Validate() error
 }
 
+// ContextValidater interface can be implemented to validate the configuration with
+// a context. LoadContext calls ValidateContext in place of Validate.
+type ContextValidater interface {
+	ValidateContext(ctx context.Context) error
+}
+
+// Warner interface can be implemented to report non-fatal configuration findings,
+// e.g. "TLS disabled in production". Warnings are logged by Load without failing it.
+type Warner interface {
+	Warn() []string
+}
+
+// Finalizer interface can be implemented to freeze or finalize the configuration
+// once all configurations have been validated
+type Finalizer interface {
+	Finalize() error
+}
+
 // Printer interface can be implemented to enable configuration output
 type Printer interface {
 	Print() interface{}
 }
 
-// Load registers, validates, and prints one or more configuration objects
+// Load registers, validates, and prints one or more configuration objects.
+// It is equivalent to LoadContext with context.Background().
 func Load(configs ...Configer) error {
-	for _, c := range configs {
-		err := c.Register()
-		if err != nil {
-			return err
-		}
-
-		v, ok := c.(Validater)
-		if ok {
-			err = v.Validate()
-			if err != nil {
-				return err
-			}
-		}
-
-		p, ok := c.(Printer)
-		if ok {
-			switch currentOutputFormat {
-			case OutputFormatJSON:
-				if err := printJSON(p); err != nil {
-					return err
-				}
-			default:
-				if err := printTable(p); err != nil {
-					return err
-				}
-			}
-		}
-	}
-
-	return nil
+	return LoadContext(context.Background(), configs...)
 }
 
-// extractFields recursively extracts fields from a struct and returns them as table rows
-func extractFields(prefix string, values reflect.Value) [][]string {
-	var data [][]string
-
-	for i := 0; i < values.NumField(); i++ {
-		field := values.Field(i)
-		structField := values.Type().Field(i)
-
-		fieldName := structField.Name
-		if prefix != "" {
-			fieldName = prefix + "." + fieldName
-		}
+// LoadContext registers, validates, and prints one or more configuration objects,
+// giving up as soon as ctx is done.
+//
+// Configurations implementing Depender are loaded after their dependencies.
+// The optional interfaces are invoked in the following order:
+//
+//  1. Defaulter.SetDefaults, ApplyDefaults, Configer.Register, Normalizer.Normalize
+//     and Validater.Validate for each configuration in load order
+//  2. CrossValidater.CrossValidate for each configuration
+//  3. Finalizer.Finalize for each configuration
+//  4. Warner.Warn and Printer.Print for each configuration
+//
+// Step 1 runs concurrently for independent configurations if enabled with
+// SetLoadConcurrency, in which case the errors of step 1 are joined.
+//
+// ContextConfiger.RegisterContext and ContextValidater.ValidateContext are called
+// in place of Register and Validate when implemented. Register and Validate do not
+// take a context, so LoadContext stops waiting for them once ctx is done and returns
+// the cause of the cancellation, leaving the call running in the background.
+//
+// LoadContext stops at the first error. Warnings reported by Warner implementations and
+// by the `warn` and `deprecated` tags of the printed configuration are passed to
+// the warning handler.
+//
+// Usage Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+//	defer cancel()
+//
+//	if err := goconf.LoadContext(ctx, new(Config)); err != nil {
+//	    log.Fatal(err)
+//	}
+func LoadContext(ctx context.Context, configs ...Configer) error {
+	return defaultLoader.LoadContext(ctx, configs...)
+}
 
-		// Check if field is marked as secret
-		secretTag, ok := structField.Tag.Lookup("secret")
-		if ok && secretTag == "true" {
-			data = append(data, []string{fieldName, SensitiveDataMaskString})
-			continue
-		}
+// loadConfig sets the defaults of a single configuration, those of its Defaulter
+// and then those of its `default` tags, then registers, normalizes and validates it
+func loadConfig(ctx context.Context, c Configer) error {
+	if err := ctx.Err(); err != nil {
+		return context.Cause(ctx)
+	}
 
-		// Handle different field types
-		switch field.Kind() {
-		case reflect.Struct:
-			// Recursively process nested structs
-			nestedData := extractFields(fieldName, field)
-			data = append(data, nestedData...)
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			data = append(data, []string{fieldName, strconv.FormatInt(field.Int(), 10)})
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			data = append(data, []string{fieldName, strconv.FormatUint(field.Uint(), 10)})
-		case reflect.Float32, reflect.Float64:
-			data = append(data, []string{fieldName, strconv.FormatFloat(field.Float(), 'f', -1, 64)})
-		case reflect.Bool:
-			data = append(data, []string{fieldName, strconv.FormatBool(field.Bool())})
-		case reflect.String:
-			data = append(data, []string{fieldName, field.String()})
-		default:
-			// For other types, use string representation
-			data = append(data, []string{fieldName, fmt.Sprintf("%v", field.Interface())})
+	d, ok := c.(Defaulter)
+	if ok {
+		if err := d.SetDefaults(); err != nil {
+			return err
 		}
 	}
 
-	return data
-}
-
-func printTable(p Printer) error {
-	table := tablewriter.NewWriter(os.Stdout)
-
-	printer := p.Print()
-
-	values := reflect.ValueOf(printer)
-	if values.Kind() == reflect.Ptr {
-		values = values.Elem()
+	if err := ApplyDefaults(c); err != nil {
+		return err
 	}
 
-	if values.Kind() == reflect.Interface {
-		values = values.Elem()
+	var err error
+	if cc, ok := c.(ContextConfiger); ok {
+		err = cc.RegisterContext(ctx)
+	} else {
+		err = callContext(ctx, c.Register)
 	}
-
-	data := extractFields("", values)
-
-	table.Header("Config", "Value")
-
-	if err := table.Bulk(data); err != nil {
-		return fmt.Errorf("failed to add table data: %w", err)
+	if err != nil {
+		return err
 	}
 
-	if err := table.Render(); err != nil {
-		return fmt.Errorf("failed to render table: %w", err)
+	n, ok := c.(Normalizer)
+	if ok {
+		if err := n.Normalize(); err != nil {
+			return err
+		}
 	}
 
-	return nil
-}
-
-// extractJSONFields recursively extracts fields from a struct and returns them as a map for JSON marshaling
-func extractJSONFields(values reflect.Value) map[string]interface{} {
-	configMap := make(map[string]interface{})
-
-	for i := 0; i < values.NumField(); i++ {
-		field := values.Field(i)
-		structField := values.Type().Field(i)
-
-		// Check if field is marked as secret
-		secretTag, ok := structField.Tag.Lookup("secret")
-		if ok && secretTag == "true" {
-			configMap[structField.Name] = SensitiveDataMaskString
-			continue
-		}
+	if cv, ok := c.(ContextValidater); ok {
+		return cv.ValidateContext(ctx)
+	}
 
-		// Handle nested structs recursively
-		if field.Kind() == reflect.Struct {
-			configMap[structField.Name] = extractJSONFields(field)
-		} else {
-			configMap[structField.Name] = field.Interface()
-		}
+	v, ok := c.(Validater)
+	if ok {
+		return callContext(ctx, v.Validate)
 	}
 
-	return configMap
+	return nil
 }
 
-func printJSON(p Printer) error {
-	printer := p.Print()
-
-	values := reflect.ValueOf(printer)
-	if values.Kind() == reflect.Ptr {
-		values = values.Elem()
+// callContext calls fn and waits for it to return or for ctx to be done,
+// whichever happens first. fn is called directly if ctx can never be done.
+func callContext(ctx context.Context, fn func() error) error {
+	if ctx.Done() == nil {
+		return fn()
 	}
 
-	if values.Kind() == reflect.Interface {
-		values = values.Elem()
+	if err := ctx.Err(); err != nil {
+		return context.Cause(ctx)
 	}
 
-	configMap := extractJSONFields(values)
+	result := make(chan error, 1)
+	go func() {
+		result <- fn()
+	}()
 
-	jsonData, err := json.MarshalIndent(configMap, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal config to JSON: %w", err)
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return context.Cause(ctx)
 	}
-
-	// Create a logger that writes to stdout with timestamp
-	logger := log.New(os.Stdout, "", log.LstdFlags)
-	logger.Println(string(jsonData))
-
-	return nil
 }
@@ -2,10 +2,12 @@ package goconf
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -306,7 +308,7 @@ func TestExtractFields(t *testing.T) {
 	}
 
 	values := reflect.ValueOf(testData)
-	result := extractFields("", values)
+	result := tableRows(buildFields(KeyNamingField, values))
 
 	// Verify field names with dot notation
 	fieldNames := make(map[string]bool)
@@ -351,7 +353,7 @@ func TestExtractJSONFields(t *testing.T) {
 	}
 
 	values := reflect.ValueOf(testData)
-	result := extractJSONFields(values)
+	result := jsonMap(KeyNamingField, buildFields(KeyNamingField, values))
 
 	assert.Equal(t, "TestApp", result["Name"])
 	assert.Equal(t, SensitiveDataMaskString, result["Password"])
@@ -362,6 +364,163 @@ func TestExtractJSONFields(t *testing.T) {
 	assert.Equal(t, SensitiveDataMaskString, dbMap["Password"])
 }
 
+func TestLoadLifecycleHooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name         string
+		defaultErr   error
+		registerErr  error
+		normalizeErr error
+		validateErr  error
+		finalizeErr  error
+		expectedErr  error
+	}{
+		{
+			name: "hooks are invoked in order",
+		},
+		{
+			name:        "defaults failure stops before register",
+			defaultErr:  errors.New("defaults failed"),
+			expectedErr: errors.New("defaults failed"),
+		},
+		{
+			name:         "normalize failure stops before validate",
+			normalizeErr: errors.New("normalize failed"),
+			expectedErr:  errors.New("normalize failed"),
+		},
+		{
+			name:        "finalize failure stops before print",
+			finalizeErr: errors.New("finalize failed"),
+			expectedErr: errors.New("finalize failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDefaulter := mocks.NewMockDefaulter(ctrl)
+			mockConfiger := mocks.NewMockConfiger(ctrl)
+			mockNormalizer := mocks.NewMockNormalizer(ctrl)
+			mockValidater := mocks.NewMockValidater(ctrl)
+			mockFinalizer := mocks.NewMockFinalizer(ctrl)
+			mockPrinter := mocks.NewMockPrinter(ctrl)
+
+			calls := []*gomock.Call{mockDefaulter.EXPECT().SetDefaults().Return(test.defaultErr)}
+			if test.defaultErr == nil {
+				calls = append(calls, mockConfiger.EXPECT().Register().Return(test.registerErr))
+			}
+			if test.defaultErr == nil && test.registerErr == nil {
+				calls = append(calls, mockNormalizer.EXPECT().Normalize().Return(test.normalizeErr))
+			}
+			if test.defaultErr == nil && test.registerErr == nil && test.normalizeErr == nil {
+				calls = append(calls,
+					mockValidater.EXPECT().Validate().Return(test.validateErr),
+					mockFinalizer.EXPECT().Finalize().Return(test.finalizeErr),
+				)
+			}
+			if test.expectedErr == nil {
+				calls = append(calls, mockPrinter.EXPECT().Print().Return(struct{ Name string }{Name: "app"}).MinTimes(1))
+			}
+			gomock.InOrder(calls...)
+
+			config := &struct {
+				*mocks.MockDefaulter
+				*mocks.MockConfiger
+				*mocks.MockNormalizer
+				*mocks.MockValidater
+				*mocks.MockFinalizer
+				*mocks.MockPrinter
+			}{
+				MockDefaulter:  mockDefaulter,
+				MockConfiger:   mockConfiger,
+				MockNormalizer: mockNormalizer,
+				MockValidater:  mockValidater,
+				MockFinalizer:  mockFinalizer,
+				MockPrinter:    mockPrinter,
+			}
+
+			r, w, _ := os.Pipe()
+			oldStdOut := os.Stdout
+			os.Stdout = w
+
+			err := Load(config)
+
+			w.Close()
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+			os.Stdout = oldStdOut
+
+			assert.Equal(t, test.expectedErr, err)
+			if err == nil {
+				assert.Contains(t, buf.String(), "│ Name   │ app   │")
+			} else {
+				assert.Empty(t, buf.String())
+			}
+		})
+	}
+}
+
+func TestLoadContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("context aware interfaces replace register and validate", func(t *testing.T) {
+		mockConfiger := mocks.NewMockConfiger(ctrl)
+		mockContextConfiger := mocks.NewMockContextConfiger(ctrl)
+		mockValidater := mocks.NewMockValidater(ctrl)
+		mockContextValidater := mocks.NewMockContextValidater(ctrl)
+
+		ctx := context.WithValue(context.Background(), struct{}{}, "value")
+		gomock.InOrder(
+			mockContextConfiger.EXPECT().RegisterContext(ctx).Return(nil),
+			mockContextValidater.EXPECT().ValidateContext(ctx).Return(nil),
+		)
+
+		config := &struct {
+			*mocks.MockConfiger
+			*mocks.MockContextConfiger
+			*mocks.MockValidater
+			*mocks.MockContextValidater
+		}{mockConfiger, mockContextConfiger, mockValidater, mockContextValidater}
+
+		assert.NoError(t, LoadContext(ctx, config))
+	})
+
+	t.Run("canceled context skips loading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := LoadContext(ctx, mocks.NewMockConfiger(ctrl))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("timeout stops waiting for a blocked register", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		mockConfiger := mocks.NewMockConfiger(ctrl)
+		mockConfiger.EXPECT().Register().DoAndReturn(func() error {
+			<-release
+			return nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := LoadContext(ctx, mockConfiger)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("register error is returned", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := LoadContext(ctx, mock(ctrl, errors.New("register failed"), nil))
+		assert.EqualError(t, err, "register failed")
+	})
+}
+
 func mock(ctrl *gomock.Controller, registerErr, validateErr error) Configer {
 	mockConfiger := mocks.NewMockConfiger(ctrl)
 	mockValidater := mocks.NewMockValidater(ctrl)
@@ -0,0 +1,331 @@
+package goconf
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// Field is a normalized configuration field handed to a Renderer.
+// Secret fields are already masked when a Renderer receives them.
+type Field struct {
+	// Name is the key of the field itself according to the key naming mode,
+	// e.g. "host" for Database.Host with KeyNamingYAML
+	Name string
+	// Key is the full output key according to the key naming mode,
+	// e.g. "database.host" with KeyNamingYAML or "DB_HOST" with KeyNamingEnv
+	Key string
+	// Path is the Go field path from the root struct, e.g. ["Database", "Host"]
+	Path []string
+	// Value holds the field value, or SensitiveDataMaskString for secret fields.
+	// Value is nil for nested structs, see Fields.
+	Value interface{}
+	// Type is the Go type of the field
+	Type reflect.Type
+	// Source is the environment variable the field is read from, empty if the
+	// field has no env tag
+	Source string
+	// Tags holds all struct tags of the field
+	Tags reflect.StructTag
+	// Secret reports whether the field is marked with `secret:"true"`
+	Secret bool
+	// Description holds the `desc` tag of the field
+	Description string
+	// Default holds the `envDefault` or `default` tag of the field, masked for secret fields
+	Default string
+	// HasDefault reports whether the field declares a default value
+	HasDefault bool
+	// IsDefault reports whether the value equals the declared default, or
+	// the zero value if the field has no default
+	IsDefault bool
+	// Unset reports whether the field is an Optional without a value,
+	// Value holds UnsetString in that case
+	Unset bool
+	// Fields holds the fields of a nested struct
+	Fields []Field
+
+	// raw holds the unmasked value, it is only used to compare configurations
+	raw interface{}
+}
+
+// IsStruct reports whether the field is a nested struct with its own Fields
+func (f Field) IsStruct() bool {
+	return f.Value == nil && f.Fields != nil
+}
+
+// ValueString returns the field value formatted the same way as the table output
+func (f Field) ValueString() string {
+	if f.Value == nil {
+		return ""
+	}
+
+	return formatValue(reflect.ValueOf(f.Value))
+}
+
+// Renderer renders normalized configuration fields to the writer.
+// Custom renderers can be registered for an OutputFormat using RegisterRenderer.
+type Renderer interface {
+	Render(w io.Writer, fields []Field) error
+}
+
+// RendererFunc is an adapter to allow the use of ordinary functions as a Renderer
+type RendererFunc func(w io.Writer, fields []Field) error
+
+// Render calls f(w, fields)
+func (f RendererFunc) Render(w io.Writer, fields []Field) error {
+	return f(w, fields)
+}
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[OutputFormat]Renderer{
+		OutputFormatTable:    RendererFunc(renderTable),
+		OutputFormatJSON:     RendererFunc(renderJSON),
+		OutputFormatYAML:     RendererFunc(renderYAML),
+		OutputFormatDotenv:   RendererFunc(renderDotenv),
+		OutputFormatLogfmt:   RendererFunc(renderLogfmt),
+		OutputFormatMarkdown: RendererFunc(renderMarkdown),
+	}
+)
+
+// RegisterRenderer registers the renderer used for the given output format.
+// Registering a built-in format replaces the built-in renderer.
+//
+// Usage Example:
+//
+//	goconf.RegisterRenderer("company", goconf.RendererFunc(func(w io.Writer, fields []goconf.Field) error {
+//	    for _, f := range goconf.FlattenFields(fields) {
+//	        fmt.Fprintf(w, "config %s=%s\n", f.Key, f.ValueString())
+//	    }
+//	    return nil
+//	}))
+//	goconf.SetOutputFormat("company")
+func RegisterRenderer(format OutputFormat, renderer Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+
+	renderers[format] = renderer
+}
+
+// rendererFor returns the renderer of the given format, falling back to the table renderer
+func rendererFor(format OutputFormat) Renderer {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	if r, ok := renderers[format]; ok {
+		return r
+	}
+
+	return renderers[OutputFormatTable]
+}
+
+// FlattenFields returns the leaf fields of the tree in order, dropping nested struct nodes
+func FlattenFields(fields []Field) []Field {
+	var flat []Field
+
+	for _, f := range fields {
+		if f.IsStruct() {
+			flat = append(flat, FlattenFields(f.Fields)...)
+			continue
+		}
+
+		flat = append(flat, f)
+	}
+
+	return flat
+}
+
+// FieldLister is implemented by configurations that list their own fields, such as
+// the code generated by goconf gen, so printing does not use reflection. ListFields
+// returns the fields of the Printer output in the given key naming mode, with the
+// values of secret fields unmasked; goconf masks them before rendering.
+type FieldLister interface {
+	ListFields(naming KeyNaming) []Field
+}
+
+// printConfig renders the Printer output using the current output format
+func printConfig(w io.Writer, p Printer) error {
+	fields := printerFields(currentKeyNaming, p)
+	if currentPrintMode == PrintModeChanged {
+		fields = changedFields(fields)
+	}
+
+	return rendererFor(currentOutputFormat).Render(w, fields)
+}
+
+// printerFields returns the fields of the Printer output, listed by the
+// configuration itself if it implements FieldLister. FieldLister does not know
+// the derived env names, so it is not used while they are enabled.
+func printerFields(naming KeyNaming, p Printer) []Field {
+	if l, ok := p.(FieldLister); ok && !currentEnvNaming.Derive {
+		return maskFields(l.ListFields(naming))
+	}
+
+	return buildFields(naming, printerValue(p))
+}
+
+// maskFields masks the values and defaults of the secret fields listed by a
+// FieldLister, keeping the values for comparisons
+func maskFields(fields []Field) []Field {
+	for i := range fields {
+		f := &fields[i]
+
+		if f.Fields == nil {
+			f.raw = f.Value
+		}
+
+		if f.Secret {
+			f.Value = SensitiveDataMaskString
+			if f.Default != "" {
+				f.Default = SensitiveDataMaskString
+			}
+		}
+
+		if f.Fields != nil {
+			f.Fields = maskFields(f.Fields)
+		}
+	}
+
+	return fields
+}
+
+// printerValue returns the struct value behind the Printer output
+func printerValue(p Printer) reflect.Value {
+	return structValue(p.Print())
+}
+
+// structValue dereferences pointers and interfaces to the underlying struct value
+func structValue(config interface{}) reflect.Value {
+	values := reflect.ValueOf(config)
+	if values.Kind() == reflect.Ptr {
+		values = values.Elem()
+	}
+
+	if values.Kind() == reflect.Interface {
+		values = values.Elem()
+	}
+
+	return values
+}
+
+// buildFields builds the normalized field tree of a struct using the given key naming mode
+func buildFields(naming KeyNaming, values reflect.Value) []Field {
+	return buildNestedFields(naming, "", nil, RootEnvPath(), values)
+}
+
+// buildNestedFields builds the fields of a struct nested under keyPrefix and path.
+// env carries the environment variable prefixes of the parent structs.
+func buildNestedFields(naming KeyNaming, keyPrefix string, path []string, env EnvPath, values reflect.Value) []Field {
+	fields := make([]Field, 0, values.NumField())
+
+	for i := 0; i < values.NumField(); i++ {
+		value := values.Field(i)
+		structField := values.Type().Field(i)
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		f := Field{
+			Name:   keyName(naming, structField),
+			Path:   append(append([]string(nil), path...), structField.Name),
+			Type:   structField.Type,
+			Tags:   structField.Tag,
+			Secret: isSecret(structField),
+		}
+
+		f.Key = f.Name
+		if keyPrefix != "" {
+			f.Key = keyPrefix + "." + f.Name
+		}
+
+		setMetadata(&f, structField, value)
+
+		isOptional := isOptionalType(value.Type())
+		isStruct := value.Kind() == reflect.Struct && !isOptional && !isEnvLeaf(value.Type())
+		if name, ok := env.Key(structField); ok && !isStruct {
+			f.Source = name
+			if naming == KeyNamingEnv {
+				f.Name = name
+				f.Key = name
+			}
+		}
+
+		switch {
+		case f.Secret:
+			f.Value = SensitiveDataMaskString
+		case isStruct:
+			f.Fields = buildNestedFields(naming, f.Key, f.Path, env.Nested(structField), value)
+		case isOptional:
+			f.Value, f.Unset = optionalFieldValue(value)
+		default:
+			f.Value = value.Interface()
+		}
+
+		if f.Fields == nil {
+			f.raw = value.Interface()
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+// optionalFieldValue returns the value of an Optional field for printing, or
+// UnsetString and true if it is not set
+func optionalFieldValue(value reflect.Value) (interface{}, bool) {
+	ptr := reflect.ValueOf(value.Interface().(optional).optionalValue())
+	if ptr.IsNil() {
+		return UnsetString, true
+	}
+
+	return ptr.Elem().Interface(), false
+}
+
+// formatValue formats a single non-struct field value for printing
+func formatValue(field reflect.Value) string {
+	// Named values such as ByteSize print as their text, e.g. 512MiB
+	if field.Kind() != reflect.Struct && field.Kind() != reflect.Ptr && field.Kind() != reflect.Interface {
+		if marshaler, ok := field.Interface().(encoding.TextMarshaler); ok {
+			if text, err := marshaler.MarshalText(); err == nil {
+				return string(text)
+			}
+		}
+	}
+
+	// Handle different field types
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(field.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool())
+	case reflect.String:
+		return field.String()
+	case reflect.Struct:
+		// Types such as url.URL implement fmt.Stringer on the pointer
+		ptr := reflect.New(field.Type())
+		ptr.Elem().Set(field)
+		if stringer, ok := ptr.Interface().(fmt.Stringer); ok {
+			return stringer.String()
+		}
+
+		return fmt.Sprintf("%v", field.Interface())
+	default:
+		// For other types, use string representation
+		return fmt.Sprintf("%v", field.Interface())
+	}
+}
+
+// isSecret reports whether a field is marked as secret
+func isSecret(field reflect.StructField) bool {
+	secretTag, ok := field.Tag.Lookup("secret")
+	return ok && secretTag == "true"
+}
@@ -0,0 +1,145 @@
+package goconf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFields(t *testing.T) {
+	fields := buildFields(KeyNamingYAML, reflect.ValueOf(newFormatsConfig()))
+	require.Len(t, fields, 4)
+
+	assert.Equal(t, Field{
+		Name:   "app_name",
+		Key:    "app_name",
+		Path:   []string{"AppName"},
+		Value:  "My App",
+		Type:   reflect.TypeOf(""),
+		Source: "APP_NAME",
+		Tags:   `env:"APP_NAME" yaml:"app_name"`,
+		raw:    "My App",
+	}, fields[0])
+	assert.Empty(t, fields[2].Source, "Internal has no env tag")
+
+	database := fields[3]
+	assert.True(t, database.IsStruct())
+	assert.Nil(t, database.Value)
+	require.Len(t, database.Fields, 2)
+
+	password := database.Fields[1]
+	assert.Equal(t, "database.password", password.Key)
+	assert.Equal(t, []string{"Database", "Password"}, password.Path)
+	assert.Equal(t, "DB_PASSWORD", password.Source)
+	assert.True(t, password.Secret)
+	assert.Equal(t, SensitiveDataMaskString, password.Value)
+	assert.False(t, password.IsStruct())
+}
+
+func TestBuildFieldsSkipsUnexportedFields(t *testing.T) {
+	type config struct {
+		Name  string
+		token string
+	}
+
+	fields := buildFields(KeyNamingField, reflect.ValueOf(config{Name: "app", token: "hidden"}))
+	require.Len(t, fields, 1)
+	assert.Equal(t, "Name", fields[0].Key)
+}
+
+func TestFlattenFields(t *testing.T) {
+	fields := buildFields(KeyNamingField, reflect.ValueOf(newFormatsConfig()))
+
+	var keys []string
+	for _, f := range FlattenFields(fields) {
+		keys = append(keys, f.Key)
+	}
+
+	assert.Equal(t, []string{"AppName", "Port", "Internal", "Database.Host", "Database.Password"}, keys)
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	originalFormat := currentOutputFormat
+	defer func() { currentOutputFormat = originalFormat }()
+
+	const format OutputFormat = "company"
+	defer func() {
+		renderersMu.Lock()
+		delete(renderers, format)
+		renderersMu.Unlock()
+	}()
+
+	RegisterRenderer(format, RendererFunc(func(w io.Writer, fields []Field) error {
+		for _, f := range FlattenFields(fields) {
+			if _, err := fmt.Fprintf(w, "config %s=%s\n", f.Key, f.ValueString()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+	SetOutputFormat(format)
+
+	output := captureStdout(t, func() error {
+		return Load(printerMock(ctrl, newFormatsConfig()))
+	})
+
+	assert.Equal(t, `config AppName=My App
+config Port=8080
+config Internal=a|b
+config Database.Host=localhost
+config Database.Password=***************
+`, output)
+}
+
+func TestUnknownOutputFormatFallsBackToTable(t *testing.T) {
+	originalFormat := currentOutputFormat
+	defer func() { currentOutputFormat = originalFormat }()
+
+	SetOutputFormat("unknown")
+
+	var buf bytes.Buffer
+	err := printConfig(&buf, printerMock(gomock.NewController(t), newFormatsConfig()).(Printer))
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "│ Database.Host     │ localhost       │")
+}
+
+// listedConfig lists its fields with the unmasked password, like generated code
+type listedConfig struct {
+	formatsConfig
+}
+
+func (c listedConfig) Print() interface{} {
+	return c.formatsConfig
+}
+
+func (c listedConfig) ListFields(naming KeyNaming) []Field {
+	fields := buildFields(naming, reflect.ValueOf(c.formatsConfig))
+	fields[3].Fields[1].Value = c.Database.Password
+	fields[3].Fields[1].Default = "default-secret"
+
+	return fields
+}
+
+func TestPrinterFieldsOfFieldLister(t *testing.T) {
+	cfg := newFormatsConfig()
+
+	fields := printerFields(KeyNamingYAML, listedConfig{cfg})
+
+	expected := buildFields(KeyNamingYAML, reflect.ValueOf(cfg))
+	expected[3].Fields[1].Default = SensitiveDataMaskString
+	assert.Equal(t, expected, fields)
+	assert.Equal(t, "db-secret", fields[3].Fields[1].raw)
+
+	assert.Equal(t, buildFields(KeyNamingEnv, reflect.ValueOf(cfg)), printerFields(KeyNamingEnv, printerMock(gomock.NewController(t), cfg).(Printer)))
+}
@@ -0,0 +1,416 @@
+package goconf
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// builtinRules are the configuration specific validation rules registered on every Validator
+var builtinRules = map[string]validator.Func{
+	"port":           isPortRule,
+	"hostport":       isHostPort,
+	"duration_range": isDurationInRange,
+	"file_exists":    isExistingFile,
+	"dir_writable":   isWritableDir,
+	"cidr_list":      isCIDRList,
+	"crontab":        isCronExpression,
+	"regexp":         isRegexp,
+	"tz":             isTimeZone,
+	"pem_cert":       isPEMCertificate,
+	"pem_key":        isPEMPrivateKey,
+	"dsn":            isDSN,
+	"loglevel":       isLogLevel,
+}
+
+// registerBuiltinRules registers the builtin rules on a go-playground validator
+func registerBuiltinRules(v *validator.Validate) {
+	for tag, fn := range builtinRules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(fmt.Sprintf("goconf: failed to register %s validation: %v", tag, err))
+		}
+	}
+}
+
+// stringValues returns the values of a string field, a comma separated string
+// is not split. A []string field returns all elements.
+func stringValues(field reflect.Value) []string {
+	switch field.Kind() {
+	case reflect.String:
+		return []string{field.String()}
+	case reflect.Slice, reflect.Array:
+		values := make([]string, 0, field.Len())
+		for i := 0; i < field.Len(); i++ {
+			values = append(values, fmt.Sprint(field.Index(i).Interface()))
+		}
+
+		return values
+	default:
+		return []string{fmt.Sprint(field.Interface())}
+	}
+}
+
+// isPortRule validates a TCP/UDP port number between 1 and 65535 given as an
+// integer or a string
+func isPortRule(fl validator.FieldLevel) bool {
+	field := fl.Field()
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int() >= 1 && field.Int() <= 65535
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint() >= 1 && field.Uint() <= 65535
+	case reflect.String:
+		return isPortString(field.String())
+	default:
+		return false
+	}
+}
+
+func isPortString(value string) bool {
+	port, err := strconv.ParseUint(value, 10, 16)
+
+	return err == nil && port >= 1
+}
+
+// isHostPort validates a host:port pair such as localhost:8080 or :8080
+func isHostPort(fl validator.FieldLevel) bool {
+	_, port, err := net.SplitHostPort(fl.Field().String())
+
+	return err == nil && isPortString(port)
+}
+
+// isDurationInRange validates a duration against a min:max parameter, e.g.
+// `validate:"duration_range=1s:5m"`. Either bound can be omitted.
+func isDurationInRange(fl validator.FieldLevel) bool {
+	field := fl.Field()
+
+	var d time.Duration
+	switch {
+	case field.Kind() == reflect.String:
+		parsed, err := time.ParseDuration(field.String())
+		if err != nil {
+			return false
+		}
+		d = parsed
+	case field.Kind() == reflect.Int64:
+		d = time.Duration(field.Int())
+	default:
+		return false
+	}
+
+	minParam, maxParam, ok := strings.Cut(fl.Param(), ":")
+	if !ok {
+		panic(fmt.Sprintf("goconf: invalid duration_range parameter %q, expected min:max", fl.Param()))
+	}
+
+	if minParam != "" && d < mustParseDuration(minParam) {
+		return false
+	}
+
+	if maxParam != "" && d > mustParseDuration(maxParam) {
+		return false
+	}
+
+	return true
+}
+
+func mustParseDuration(value string) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		panic(fmt.Sprintf("goconf: invalid duration_range parameter: %v", err))
+	}
+
+	return d
+}
+
+// isExistingFile validates that the path points to an existing regular file
+func isExistingFile(fl validator.FieldLevel) bool {
+	info, err := os.Stat(fl.Field().String())
+
+	return err == nil && info.Mode().IsRegular()
+}
+
+// isWritableDir validates that the path points to an existing directory the
+// process can create files in
+func isWritableDir(fl validator.FieldLevel) bool {
+	dir := fl.Field().String()
+
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+
+	f, err := os.CreateTemp(dir, ".goconf-write-check-*")
+	if err != nil {
+		return false
+	}
+
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+
+	return true
+}
+
+// isCIDRList validates a comma separated string or a slice of CIDR notation networks
+func isCIDRList(fl validator.FieldLevel) bool {
+	var cidrs []string
+	for _, value := range stringValues(fl.Field()) {
+		cidrs = append(cidrs, strings.Split(value, ",")...)
+	}
+
+	if len(cidrs) == 0 {
+		return false
+	}
+
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(strings.TrimSpace(cidr)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cronFieldRanges holds the allowed ranges of the standard cron fields
+// minute, hour, day of month, month and day of week
+var cronFieldRanges = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// cronNames maps month and weekday names to their values, keyed by field index
+var cronNames = map[int]map[string]int{
+	3: {"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12},
+	4: {"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6},
+}
+
+// isCronExpression validates a standard five field cron expression or one of the
+// descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly and @every <duration>
+func isCronExpression(fl validator.FieldLevel) bool {
+	expr := strings.TrimSpace(fl.Field().String())
+
+	if strings.HasPrefix(expr, "@") {
+		switch expr {
+		case "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly":
+			return true
+		}
+
+		every, ok := strings.CutPrefix(expr, "@every ")
+		if !ok {
+			return false
+		}
+
+		d, err := time.ParseDuration(every)
+
+		return err == nil && d > 0
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFieldRanges) {
+		return false
+	}
+
+	for i, field := range fields {
+		if !isCronField(field, i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isCronField validates a single cron field such as */5, 1-5, mon-fri or 1,15
+func isCronField(field string, index int) bool {
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n < 1 {
+				return false
+			}
+		}
+
+		if rangePart == "*" {
+			continue
+		}
+
+		low, high, isRange := strings.Cut(rangePart, "-")
+
+		lowValue, ok := cronValue(low, index)
+		if !ok {
+			return false
+		}
+
+		if !isRange {
+			continue
+		}
+
+		highValue, ok := cronValue(high, index)
+		if !ok || highValue < lowValue {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cronValue parses a cron field value and checks it against the field range
+func cronValue(value string, index int) (int, bool) {
+	if n, ok := cronNames[index][strings.ToLower(value)]; ok {
+		return n, true
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, n >= cronFieldRanges[index][0] && n <= cronFieldRanges[index][1]
+}
+
+// isRegexp validates that the value compiles as a regular expression
+func isRegexp(fl validator.FieldLevel) bool {
+	_, err := regexp.Compile(fl.Field().String())
+
+	return err == nil
+}
+
+// isTimeZone validates that the value is a loadable IANA time zone such as Europe/Berlin
+func isTimeZone(fl validator.FieldLevel) bool {
+	tz := fl.Field().String()
+	if tz == "" {
+		return false
+	}
+
+	_, err := time.LoadLocation(tz)
+
+	return err == nil
+}
+
+// pemData returns the PEM content of the value, reading it from a file if the
+// value is a path instead of PEM encoded data
+func pemData(value string) []byte {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value)
+	}
+
+	data, err := os.ReadFile(value)
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
+
+// isPEMCertificate validates PEM encoded x509 certificates, given inline or as a file path
+func isPEMCertificate(fl validator.FieldLevel) bool {
+	rest := pemData(fl.Field().String())
+
+	found := false
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return found
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return false
+		}
+
+		found = true
+	}
+}
+
+// isPEMPrivateKey validates a PEM encoded PKCS#1, PKCS#8 or EC private key,
+// given inline or as a file path
+func isPEMPrivateKey(fl validator.FieldLevel) bool {
+	rest := pemData(fl.Field().String())
+
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return false
+		}
+
+		var err error
+		switch block.Type {
+		case "PRIVATE KEY":
+			_, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			_, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			_, err = x509.ParseECPrivateKey(block.Bytes)
+		default:
+			continue
+		}
+
+		return err == nil
+	}
+}
+
+// mysqlDSNRegex matches the go-sql-driver DSN format [user[:password]@][net[(addr)]]/dbname[?params]
+var mysqlDSNRegex = regexp.MustCompile(`^(?:[^:@/]+(?::[^@]*)?@)?(?:[a-z0-9]+(?:\([^)]*\))?)?/[^?]*(?:\?.*)?$`)
+
+// isDSN validates a database connection string. The parameter selects the
+// driver, e.g. `validate:"dsn=postgres"`. Supported drivers are postgres and mysql.
+func isDSN(fl validator.FieldLevel) bool {
+	dsn := fl.Field().String()
+
+	switch fl.Param() {
+	case "postgres", "postgresql":
+		return isPostgresDSN(dsn)
+	case "mysql":
+		return mysqlDSNRegex.MatchString(dsn)
+	case "":
+		return isPostgresDSN(dsn) || mysqlDSNRegex.MatchString(dsn)
+	default:
+		panic(fmt.Sprintf("goconf: unsupported dsn driver %q", fl.Param()))
+	}
+}
+
+// isPostgresDSN validates a postgres URL or a key=value connection string
+func isPostgresDSN(dsn string) bool {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+
+		return err == nil && (u.Host != "" || u.Query().Get("host") != "")
+	}
+
+	fields := strings.Fields(dsn)
+	if len(fields) == 0 {
+		return false
+	}
+
+	for _, field := range fields {
+		key, _, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// logLevels holds the accepted log level names
+var logLevels = map[string]bool{
+	"trace": true, "debug": true, "info": true, "warn": true, "warning": true,
+	"error": true, "fatal": true, "panic": true,
+}
+
+// isLogLevel validates a case insensitive log level name such as debug, info, warn or error
+func isLogLevel(fl validator.FieldLevel) bool {
+	return logLevels[strings.ToLower(fl.Field().String())]
+}
@@ -0,0 +1,103 @@
+package spec
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteEnvExample writes a commented .env.example file listing every field with an
+// env tag. Defaults are filled in, secrets and fields without a default are left
+// blank, and each variable is preceded by its description, validation rules and
+// deprecation notice.
+//
+// Variables of nested structs are grouped under the struct's field path.
+//
+// Example output:
+//
+//	# Database
+//
+//	# Database host
+//	# Rules: required,hostname
+//	DB_HOST=localhost
+//
+//	# Secret
+//	DB_PASSWORD=
+func WriteEnvExample(w io.Writer, fields []Field) error {
+	var b strings.Builder
+
+	writeEnvGroup(&b, fields)
+
+	_, err := io.WriteString(w, strings.TrimLeft(b.String(), "\n"))
+
+	return err
+}
+
+// writeEnvGroup writes the variables of a struct followed by the groups of its
+// nested structs, so that every variable is listed under its own group
+func writeEnvGroup(b *strings.Builder, fields []Field) {
+	for _, f := range fields {
+		if f.IsStruct() || f.Env == "" {
+			continue
+		}
+
+		b.WriteString("\n")
+
+		if f.Description != "" {
+			fmt.Fprintf(b, "# %s\n", f.Description)
+		}
+
+		if f.Validate != "" {
+			fmt.Fprintf(b, "# Rules: %s\n", f.Validate)
+		}
+
+		switch {
+		case f.Deprecated != "":
+			fmt.Fprintf(b, "# Deprecated: %s\n", f.Deprecated)
+		case f.IsDeprecated():
+			b.WriteString("# Deprecated\n")
+		}
+
+		value := ""
+		switch {
+		case f.Secret:
+			b.WriteString("# Secret\n")
+		case f.HasDefault:
+			value = quoteEnvValue(f.Default)
+		}
+
+		fmt.Fprintf(b, "%s=%s\n", f.Env, value)
+	}
+
+	for _, f := range fields {
+		if !f.IsStruct() || !hasEnv(f.Fields) {
+			continue
+		}
+
+		fmt.Fprintf(b, "\n# %s\n", strings.Join(f.Path, "."))
+		writeEnvGroup(b, f.Fields)
+	}
+}
+
+// hasEnv reports whether any field of the tree has an env tag
+func hasEnv(fields []Field) bool {
+	for _, f := range Flatten(fields) {
+		if f.Env != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quoteEnvValue double quotes value if it contains characters with a special
+// meaning in .env files
+func quoteEnvValue(value string) string {
+	if !strings.ContainsAny(value, " \t#'\"\\$=\n") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,48 @@
+package spec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEnvExample(t *testing.T) {
+	fields, err := FromValue(testConfig{})
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, WriteEnvExample(&b, fields))
+
+	assert.Equal(t, `# Application name
+# Rules: required,min=3
+APP_NAME=app
+
+TIMEOUT=30s
+
+RETRIES=
+
+HOSTS=
+
+# Deprecated
+LEGACY=
+
+# Database
+
+DB_HOST=
+
+# Secret
+DB_PASSWORD=
+
+# Common
+
+REGION=
+`, b.String())
+}
+
+func TestQuoteEnvValue(t *testing.T) {
+	assert.Equal(t, "plain", quoteEnvValue("plain"))
+	assert.Equal(t, `"my app"`, quoteEnvValue("my app"))
+	assert.Equal(t, `"say \"hi\"\n"`, quoteEnvValue("say \"hi\"\n"))
+}
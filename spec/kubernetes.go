@@ -0,0 +1,210 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KubernetesEnvStyle selects how a container references the ConfigMap and Secret
+type KubernetesEnvStyle string
+
+const (
+	// KubernetesEnvFrom imports all keys of the ConfigMap and Secret with envFrom
+	KubernetesEnvFrom KubernetesEnvStyle = "envFrom"
+	// KubernetesEnv lists every variable with a configMapKeyRef or secretKeyRef
+	KubernetesEnv KubernetesEnvStyle = "env"
+)
+
+// WriteKubernetesManifests writes a ConfigMap holding the environment variables of
+// the fields that are not secret and a Secret stub holding the secret fields, both
+// named name. Defaults are filled in, secrets and fields without a default are left
+// empty. A manifest without variables is left out.
+//
+// Example output:
+//
+//	apiVersion: v1
+//	kind: ConfigMap
+//	metadata:
+//	  name: my-app
+//	data:
+//	  # HTTP listen port
+//	  PORT: "8080"
+//	---
+//	apiVersion: v1
+//	kind: Secret
+//	metadata:
+//	  name: my-app
+//	type: Opaque
+//	stringData:
+//	  DB_PASSWORD: ""
+func WriteKubernetesManifests(w io.Writer, fields []Field, name string) error {
+	plain, secret := kubernetesVars(fields)
+	if len(plain) == 0 && len(secret) == 0 {
+		return errors.New("no field has an env tag")
+	}
+
+	var docs []*yaml.Node
+
+	if len(plain) > 0 {
+		data := mappingNode()
+		for _, f := range plain {
+			value := scalarNode("")
+			if f.HasDefault {
+				value = scalarNode(f.Default)
+			}
+
+			key := scalarNode(f.Env)
+			key.HeadComment = kubernetesComment(f)
+			data.Content = append(data.Content, key, value)
+		}
+
+		docs = append(docs, mappingNode(
+			"apiVersion", scalarNode("v1"),
+			"kind", scalarNode("ConfigMap"),
+			"metadata", mappingNode("name", scalarNode(name)),
+			"data", data,
+		))
+	}
+
+	if len(secret) > 0 {
+		data := mappingNode()
+		for _, f := range secret {
+			key := scalarNode(f.Env)
+			key.HeadComment = kubernetesComment(f)
+			data.Content = append(data.Content, key, scalarNode(""))
+		}
+
+		docs = append(docs, mappingNode(
+			"apiVersion", scalarNode("v1"),
+			"kind", scalarNode("Secret"),
+			"metadata", mappingNode("name", scalarNode(name)),
+			"type", scalarNode("Opaque"),
+			"stringData", data,
+		))
+	}
+
+	return encodeYAML(w, docs...)
+}
+
+// WriteKubernetesEnv writes the part of a Deployment container spec that loads the
+// variables of the manifests written by WriteKubernetesManifests. KubernetesEnvFrom
+// references the ConfigMap and Secret as a whole, KubernetesEnv lists every variable.
+//
+// Example output of KubernetesEnvFrom:
+//
+//	envFrom:
+//	  - configMapRef:
+//	      name: my-app
+//	  - secretRef:
+//	      name: my-app
+func WriteKubernetesEnv(w io.Writer, fields []Field, name string, style KubernetesEnvStyle) error {
+	plain, secret := kubernetesVars(fields)
+	if len(plain) == 0 && len(secret) == 0 {
+		return errors.New("no field has an env tag")
+	}
+
+	refs := &yaml.Node{Kind: yaml.SequenceNode}
+
+	switch style {
+	case KubernetesEnvFrom:
+		if len(plain) > 0 {
+			refs.Content = append(refs.Content, mappingNode("configMapRef", mappingNode("name", scalarNode(name))))
+		}
+
+		if len(secret) > 0 {
+			refs.Content = append(refs.Content, mappingNode("secretRef", mappingNode("name", scalarNode(name))))
+		}
+	case KubernetesEnv:
+		for _, f := range plain {
+			refs.Content = append(refs.Content, envVarNode(f.Env, "configMapKeyRef", name))
+		}
+
+		for _, f := range secret {
+			refs.Content = append(refs.Content, envVarNode(f.Env, "secretKeyRef", name))
+		}
+	default:
+		return fmt.Errorf("unsupported Kubernetes env style %q", style)
+	}
+
+	return encodeYAML(w, mappingNode(string(style), refs))
+}
+
+// kubernetesVars returns the fields with an env tag split into plain and secret fields
+func kubernetesVars(fields []Field) ([]Field, []Field) {
+	var plain, secret []Field
+
+	for _, f := range Flatten(fields) {
+		switch {
+		case f.Env == "":
+		case f.Secret:
+			secret = append(secret, f)
+		default:
+			plain = append(plain, f)
+		}
+	}
+
+	return plain, secret
+}
+
+// kubernetesComment returns the comment of a variable, its description and deprecation notice
+func kubernetesComment(f Field) string {
+	comment := f.Description
+
+	deprecation := ""
+	switch {
+	case f.Deprecated != "":
+		deprecation = "Deprecated: " + f.Deprecated
+	case f.IsDeprecated():
+		deprecation = "Deprecated"
+	}
+
+	if comment != "" && deprecation != "" {
+		return comment + "\n" + deprecation
+	}
+
+	return comment + deprecation
+}
+
+// envVarNode returns a container env entry reading the variable from a ConfigMap or Secret key
+func envVarNode(env, refKind, name string) *yaml.Node {
+	return mappingNode(
+		"name", scalarNode(env),
+		"valueFrom", mappingNode(refKind, mappingNode(
+			"name", scalarNode(name),
+			"key", scalarNode(env),
+		)),
+	)
+}
+
+// mappingNode returns a YAML mapping of alternating string keys and value nodes
+func mappingNode(pairs ...interface{}) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+
+	for i := 0; i+1 < len(pairs); i += 2 {
+		node.Content = append(node.Content, scalarNode(pairs[i].(string)), pairs[i+1].(*yaml.Node))
+	}
+
+	return node
+}
+
+// scalarNode returns a YAML string, quoted if it would otherwise be read as another type
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// encodeYAML writes the nodes as YAML documents indented by two spaces
+func encodeYAML(w io.Writer, docs ...*yaml.Node) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	}
+
+	return encoder.Close()
+}
@@ -0,0 +1,98 @@
+package spec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteKubernetesManifests(t *testing.T) {
+	fields, err := FromValue(testConfig{})
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, WriteKubernetesManifests(&b, fields, "my-app"))
+
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app
+data:
+  # Application name
+  APP_NAME: app
+  TIMEOUT: 30s
+  RETRIES: ""
+  HOSTS: ""
+  # Deprecated
+  LEGACY: ""
+  DB_HOST: ""
+  REGION: ""
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: my-app
+type: Opaque
+stringData:
+  DB_PASSWORD: ""
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, WriteKubernetesManifests(&b, []Field{{Name: "Port", Env: "PORT", Default: "8080", HasDefault: true}}, "my-app"))
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app
+data:
+  PORT: "8080"
+`, b.String())
+
+	assert.EqualError(t, WriteKubernetesManifests(&b, []Field{{Name: "Port"}}, "my-app"), "no field has an env tag")
+}
+
+func TestWriteKubernetesEnv(t *testing.T) {
+	fields := []Field{
+		{Name: "Port", Env: "PORT"},
+		{Name: "Password", Env: "PASSWORD", Secret: true},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, WriteKubernetesEnv(&b, fields, "my-app", KubernetesEnvFrom))
+	assert.Equal(t, `envFrom:
+  - configMapRef:
+      name: my-app
+  - secretRef:
+      name: my-app
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, WriteKubernetesEnv(&b, fields, "my-app", KubernetesEnv))
+	assert.Equal(t, `env:
+  - name: PORT
+    valueFrom:
+      configMapKeyRef:
+        name: my-app
+        key: PORT
+  - name: PASSWORD
+    valueFrom:
+      secretKeyRef:
+        name: my-app
+        key: PASSWORD
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, WriteKubernetesEnv(&b, fields[:1], "my-app", KubernetesEnvFrom))
+	assert.Equal(t, `envFrom:
+  - configMapRef:
+      name: my-app
+`, b.String())
+
+	assert.EqualError(t, WriteKubernetesEnv(&b, fields, "my-app", "volumes"), `unsupported Kubernetes env style "volumes"`)
+}
+
+func TestKubernetesComment(t *testing.T) {
+	assert.Equal(t, "", kubernetesComment(Field{}))
+	assert.Equal(t, "Port\nDeprecated: use ADDR", kubernetesComment(Field{Description: "Port", Deprecated: "use ADDR"}))
+}
@@ -0,0 +1,139 @@
+package spec
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// ReferenceFormat selects the format of a configuration reference
+type ReferenceFormat string
+
+const (
+	// ReferenceMarkdown renders the reference as a markdown table
+	ReferenceMarkdown ReferenceFormat = "markdown"
+	// ReferenceHTML renders the reference as an HTML table
+	ReferenceHTML ReferenceFormat = "html"
+)
+
+// referenceColumns are the column headers of the configuration reference
+var referenceColumns = []string{"Env", "YAML", "Type", "Default", "Rules", "Secret", "Description"}
+
+// WriteReference writes a configuration reference table listing every field with
+// its environment variable, YAML path, type, default, validation rules in words,
+// whether it is secret and its description. Defaults of secret fields are not shown.
+//
+// Usage Example:
+//
+//	fields, err := spec.FromValue(Config{})
+//	if err != nil {
+//	    // Handle non struct config
+//	}
+//
+//	if err := spec.WriteReference(os.Stdout, fields, spec.ReferenceMarkdown); err != nil {
+//	    // Handle write error
+//	}
+func WriteReference(w io.Writer, fields []Field, format ReferenceFormat) error {
+	rows := referenceRows(fields)
+
+	var b strings.Builder
+
+	switch format {
+	case ReferenceMarkdown:
+		writeMarkdownTable(&b, rows)
+	case ReferenceHTML:
+		writeHTMLTable(&b, rows)
+	default:
+		return fmt.Errorf("unsupported reference format %q", format)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// referenceRows returns one row per leaf field in the order of referenceColumns
+func referenceRows(fields []Field) [][]string {
+	flat := Flatten(fields)
+	rows := make([][]string, 0, len(flat))
+
+	for _, f := range flat {
+		typeName := f.Type.Name
+		if f.Optional {
+			typeName += " (optional)"
+		}
+
+		defaultValue := f.Default
+		if f.Secret && f.HasDefault {
+			defaultValue = "(hidden)"
+		}
+
+		secret := "no"
+		if f.Secret {
+			secret = "yes"
+		}
+
+		description := f.Description
+		switch {
+		case f.Deprecated != "":
+			description = strings.TrimSpace(description + " Deprecated: " + f.Deprecated)
+		case f.IsDeprecated():
+			description = strings.TrimSpace(description + " Deprecated.")
+		}
+
+		rows = append(rows, []string{f.Env, f.YAML, typeName, defaultValue, DescribeRules(f), secret, description})
+	}
+
+	return rows
+}
+
+func writeMarkdownTable(b *strings.Builder, rows [][]string) {
+	b.WriteString("| " + strings.Join(referenceColumns, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat("---|", len(referenceColumns)) + "\n")
+
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = markdownCell(cell, i < 4)
+		}
+
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+}
+
+// markdownCell escapes a table cell, code cells are wrapped in backticks
+func markdownCell(value string, code bool) string {
+	if value == "" {
+		return ""
+	}
+
+	value = strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(value)
+	if code {
+		return "`" + value + "`"
+	}
+
+	return value
+}
+
+func writeHTMLTable(b *strings.Builder, rows [][]string) {
+	b.WriteString("<table>\n  <thead>\n    <tr>")
+	for _, column := range referenceColumns {
+		b.WriteString("<th>" + column + "</th>")
+	}
+	b.WriteString("</tr>\n  </thead>\n  <tbody>\n")
+
+	for _, row := range rows {
+		b.WriteString("    <tr>")
+		for i, cell := range row {
+			cell = html.EscapeString(cell)
+			if i < 4 && cell != "" {
+				cell = "<code>" + cell + "</code>"
+			}
+			b.WriteString("<td>" + cell + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("  </tbody>\n</table>\n")
+}
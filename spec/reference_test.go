@@ -0,0 +1,51 @@
+package spec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteReference(t *testing.T) {
+	fields := []Field{
+		{
+			Name: "Port", Env: "PORT", YAML: "port", Type: Type{Name: "int", Kind: KindInt},
+			Default: "8080", HasDefault: true, Validate: "gte=1024", Description: "Listen port | TCP",
+		},
+		{
+			Name: "Password", Env: "DB_PASSWORD", YAML: "database.password", Type: Type{Name: "string", Kind: KindString},
+			Default: "secret", HasDefault: true, Secret: true, Tags: `deprecated:"use a vault"`, Deprecated: "use a vault",
+		},
+		{
+			Name: "Retries", YAML: "retries", Type: Type{Name: "int", Kind: KindInt}, Optional: true, Description: "<retries>",
+		},
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, WriteReference(&b, fields, ReferenceMarkdown))
+
+		assert.Equal(t, "| Env | YAML | Type | Default | Rules | Secret | Description |\n"+
+			"|---|---|---|---|---|---|---|\n"+
+			"| `PORT` | `port` | `int` | `8080` | at least 1024 | no | Listen port \\| TCP |\n"+
+			"| `DB_PASSWORD` | `database.password` | `string` | `(hidden)` |  | yes | Deprecated: use a vault |\n"+
+			"|  | `retries` | `int (optional)` |  |  | no | <retries> |\n", b.String())
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, WriteReference(&b, fields[2:], ReferenceHTML))
+
+		assert.Equal(t, "<table>\n  <thead>\n    <tr><th>Env</th><th>YAML</th><th>Type</th><th>Default</th>"+
+			"<th>Rules</th><th>Secret</th><th>Description</th></tr>\n  </thead>\n  <tbody>\n"+
+			"    <tr><td></td><td><code>retries</code></td><td><code>int (optional)</code></td><td></td>"+
+			"<td></td><td>no</td><td>&lt;retries&gt;</td></tr>\n  </tbody>\n</table>\n", b.String())
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		err := WriteReference(&bytes.Buffer{}, fields, "pdf")
+		assert.EqualError(t, err, `unsupported reference format "pdf"`)
+	})
+}
@@ -0,0 +1,136 @@
+package spec
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/wgarunap/goconf"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// optionalPkgPath is the package path of goconf.Optional
+const optionalPkgPath = "github.com/wgarunap/goconf"
+
+// FromValue describes the fields of config, which must be a struct or a pointer to a struct
+//
+// Usage Example:
+//
+//	fields, err := spec.FromValue(Config{})
+//	if err != nil {
+//	    // Handle non struct config
+//	}
+func FromValue(config interface{}) ([]Field, error) {
+	return FromType(reflect.TypeOf(config))
+}
+
+// FromType describes the fields of the struct type t or a pointer to it
+func FromType(t reflect.Type) ([]Field, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errUnsupported(reflectTypeName(t))
+	}
+
+	return build(reflectFields(t), nil, goconf.RootEnvPath(), "")
+}
+
+func reflectFields(t reflect.Type) []structField {
+	fields := make([]structField, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fieldType := sf.Type
+
+		optional := isReflectOptional(fieldType)
+		if optional {
+			fieldType = fieldType.Field(0).Type
+		}
+
+		nested := fieldType
+		for nested.Kind() == reflect.Ptr {
+			nested = nested.Elem()
+		}
+
+		fields = append(fields, structField{
+			name:      sf.Name,
+			tag:       sf.Tag,
+			exported:  sf.IsExported(),
+			anonymous: sf.Anonymous,
+			typ:       reflectType(fieldType),
+			optional:  optional,
+			fields: func() ([]structField, error) {
+				return reflectFields(nested), nil
+			},
+		})
+	}
+
+	return fields
+}
+
+// reflectType describes a reflect.Type
+func reflectType(t reflect.Type) Type {
+	result := Type{Name: t.String()}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == durationType:
+		result.Kind = KindDuration
+		return result
+	case t.Kind() != reflect.String && reflect.PointerTo(t).Implements(textUnmarshalerType):
+		result.Kind = KindText
+		return result
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		result.Kind = KindString
+	case reflect.Bool:
+		result.Kind = KindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		result.Kind = KindInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		result.Kind = KindUint
+	case reflect.Float32, reflect.Float64:
+		result.Kind = KindFloat
+	case reflect.Slice, reflect.Array:
+		elem := reflectType(t.Elem())
+		result.Kind = KindSlice
+		result.Elem = &elem
+	case reflect.Map:
+		key, elem := reflectType(t.Key()), reflectType(t.Elem())
+		result.Kind = KindMap
+		result.Key = &key
+		result.Elem = &elem
+	case reflect.Struct:
+		result.Kind = KindStruct
+	default:
+		result.Kind = KindOther
+	}
+
+	return result
+}
+
+// isReflectOptional reports whether t is an instance of goconf.Optional
+func isReflectOptional(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == optionalPkgPath &&
+		strings.HasPrefix(t.Name(), "Optional[") && t.NumField() > 0
+}
+
+func reflectTypeName(t reflect.Type) string {
+	if t == nil {
+		return "<nil>"
+	}
+
+	return t.String()
+}
@@ -0,0 +1,135 @@
+package spec
+
+import (
+	"strings"
+)
+
+// ruleWords holds the descriptions of validation rules without a parameter
+var ruleWords = map[string]string{
+	"required":     "required",
+	"url":          "a valid URL",
+	"uri":          "a valid URI",
+	"email":        "a valid email address",
+	"hostname":     "a valid hostname",
+	"fqdn":         "a fully qualified domain name",
+	"ip":           "a valid IP address",
+	"ipv4":         "a valid IPv4 address",
+	"ipv6":         "a valid IPv6 address",
+	"cidr":         "a valid CIDR network",
+	"uuid":         "a valid UUID",
+	"alpha":        "letters only",
+	"alphanum":     "letters and digits only",
+	"numeric":      "a number",
+	"boolean":      "a boolean",
+	"lowercase":    "lowercase",
+	"uppercase":    "uppercase",
+	"json":         "valid JSON",
+	"file":         "an existing file",
+	"dir":          "an existing directory",
+	"port":         "a valid port number",
+	"hostport":     "a valid host:port address",
+	"file_exists":  "the path of an existing file",
+	"dir_writable": "the path of a writable directory",
+	"cidr_list":    "a list of valid CIDR networks",
+	"cron":         "a valid cron expression",
+	"crontab":      "a valid crontab expression",
+	"regexp":       "a valid regular expression",
+	"tz":           "a valid time zone",
+	"pem_cert":     "a PEM encoded certificate",
+	"pem_key":      "a PEM encoded private key",
+	"dsn":          "a valid database connection string",
+	"loglevel":     "a valid log level",
+}
+
+// DescribeRules translates the validation rules of a field into words, e.g.
+// "required, at least 1024, at most 65535" for `validate:"required,gte=1024,lte=65535"`.
+// Rules without a known description are kept as they are.
+func DescribeRules(f Field) string {
+	var words []string
+
+	for _, rule := range f.Rules() {
+		if rule == "omitempty" || rule == "" {
+			continue
+		}
+
+		words = append(words, describeRule(rule, f.Type))
+	}
+
+	return strings.Join(words, ", ")
+}
+
+// describeRule translates a single validation rule into words
+func describeRule(rule string, t Type) string {
+	tag, param, hasParam := strings.Cut(rule, "=")
+	if !hasParam {
+		if words, ok := ruleWords[tag]; ok {
+			return words
+		}
+
+		return rule
+	}
+
+	switch tag {
+	case "gte":
+		return "at least " + param
+	case "lte":
+		return "at most " + param
+	case "gt":
+		return "greater than " + param
+	case "lt":
+		return "less than " + param
+	case "eq":
+		return "equal to " + param
+	case "ne":
+		return "not " + param
+	case "min":
+		return "at least " + param + lengthUnit(t)
+	case "max":
+		return "at most " + param + lengthUnit(t)
+	case "len":
+		return "exactly " + param + lengthUnit(t)
+	case "oneof":
+		return "one of " + strings.Join(strings.Fields(param), ", ")
+	case "startswith":
+		return "starts with " + param
+	case "endswith":
+		return "ends with " + param
+	case "contains":
+		return "contains " + param
+	case "duration_range":
+		minParam, maxParam, _ := strings.Cut(param, ":")
+		switch {
+		case minParam == "":
+			return "a duration of at most " + maxParam
+		case maxParam == "":
+			return "a duration of at least " + minParam
+		default:
+			return "a duration between " + minParam + " and " + maxParam
+		}
+	case "dsn":
+		return "a valid " + param + " connection string"
+	case "required_if":
+		return "required if " + param
+	case "required_unless":
+		return "required unless " + param
+	case "required_with":
+		return "required with " + param
+	case "required_without":
+		return "required without " + param
+	default:
+		return rule
+	}
+}
+
+// lengthUnit returns the unit of min, max and len rules, which limit the length
+// of strings and collections and the value of numbers
+func lengthUnit(t Type) string {
+	switch t.Kind {
+	case KindString:
+		return " characters"
+	case KindSlice, KindMap:
+		return " items"
+	default:
+		return ""
+	}
+}
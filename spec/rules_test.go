@@ -0,0 +1,33 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate string
+		kind     Kind
+		expected string
+	}{
+		{name: "no rules", expected: ""},
+		{name: "range", validate: "required,gte=1024,lte=65535", kind: KindInt, expected: "required, at least 1024, at most 65535"},
+		{name: "string length", validate: "omitempty,min=3,max=20", kind: KindString, expected: "at least 3 characters, at most 20 characters"},
+		{name: "slice length", validate: "len=2", kind: KindSlice, expected: "exactly 2 items"},
+		{name: "oneof", validate: "oneof=dev staging prod", kind: KindString, expected: "one of dev, staging, prod"},
+		{name: "goconf rules", validate: "hostport,loglevel,dsn=postgres", kind: KindString, expected: "a valid host:port address, a valid log level, a valid postgres connection string"},
+		{name: "duration range", validate: "duration_range=1s:5m", kind: KindDuration, expected: "a duration between 1s and 5m"},
+		{name: "open duration range", validate: "duration_range=:5m", kind: KindDuration, expected: "a duration of at most 5m"},
+		{name: "unknown rules are kept", validate: "custom,custom_param=1", kind: KindString, expected: "custom, custom_param=1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := Field{Validate: test.validate, Type: Type{Kind: test.kind}}
+			assert.Equal(t, test.expected, DescribeRules(f))
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect of the generated schemas
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches the durations accepted by time.ParseDuration
+const durationPattern = `^[-+]?([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$`
+
+// ruleFormats maps validation rules to JSON Schema formats
+var ruleFormats = map[string]string{
+	"url":      "uri",
+	"uri":      "uri",
+	"email":    "email",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"uuid":     "uuid",
+	"regexp":   "regex",
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) of the YAML representation of
+// the fields. Properties are named after the `yaml` tags and descriptions and
+// defaults are read from the `desc`, `envDefault` and `default` tags. The validation
+// rules required, gt, gte, lt, lte, min, max, len, eq, oneof and format rules such
+// as url or email are mapped to schema keywords. Unknown keys are rejected so that
+// typos are reported, and defaults of secret fields are left out.
+func JSONSchema(fields []Field) map[string]interface{} {
+	schema := objectSchema(fields)
+	schema["$schema"] = JSONSchemaDraft
+
+	return schema
+}
+
+// WriteJSONSchema writes the indented JSON Schema of the fields, see JSONSchema
+//
+// Usage Example:
+//
+//	fields, err := spec.FromValue(Config{})
+//	if err != nil {
+//	    // Handle non struct config
+//	}
+//
+//	if err := spec.WriteJSONSchema(os.Stdout, fields); err != nil {
+//	    // Handle write error
+//	}
+func WriteJSONSchema(w io.Writer, fields []Field) error {
+	data, err := json.MarshalIndent(JSONSchema(fields), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON schema: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
+
+	return err
+}
+
+// objectSchema returns the schema of a struct
+func objectSchema(fields []Field) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	addProperties(fields, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// addProperties adds the properties of the fields, merging inlined structs into the parent
+func addProperties(fields []Field, properties map[string]interface{}, required *[]string) {
+	for _, f := range fields {
+		name, inline := yamlKey(structField{name: f.Name, tag: f.Tags})
+		if name == "-" {
+			continue
+		}
+
+		if inline && f.IsStruct() {
+			addProperties(f.Fields, properties, required)
+			continue
+		}
+
+		properties[name] = fieldSchema(f)
+
+		// A key with a default may be left out of the file
+		if f.Required() && !f.HasDefault {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// fieldSchema returns the schema of a single field including its rules and metadata
+func fieldSchema(f Field) map[string]interface{} {
+	var schema map[string]interface{}
+	if f.IsStruct() {
+		schema = objectSchema(f.Fields)
+	} else {
+		schema = typeSchema(f.Type)
+	}
+
+	rules, itemRules := splitDive(f.Rules())
+	applyRules(schema, f.Type, rules)
+
+	if items, ok := schema["items"].(map[string]interface{}); ok && f.Type.Elem != nil {
+		applyRules(items, *f.Type.Elem, itemRules)
+	}
+
+	if typ, ok := schema["type"]; ok && f.Optional {
+		schema["type"] = []interface{}{typ, "null"}
+	}
+
+	if f.Description != "" {
+		schema["description"] = f.Description
+	}
+
+	if f.HasDefault && !f.Secret {
+		schema["default"] = defaultJSON(f.Default, f.Type)
+	}
+
+	if f.IsDeprecated() {
+		schema["deprecated"] = true
+	}
+
+	return schema
+}
+
+// typeSchema returns the schema of a type without rules
+func typeSchema(t Type) map[string]interface{} {
+	switch t.Kind {
+	case KindString, KindText:
+		return map[string]interface{}{"type": "string"}
+	case KindDuration:
+		return map[string]interface{}{"type": "string", "pattern": durationPattern}
+	case KindBool:
+		return map[string]interface{}{"type": "boolean"}
+	case KindInt:
+		return map[string]interface{}{"type": "integer"}
+	case KindUint:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case KindFloat:
+		return map[string]interface{}{"type": "number"}
+	case KindSlice:
+		return map[string]interface{}{"type": "array", "items": typeSchema(*t.Elem)}
+	case KindMap:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(*t.Elem)}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// splitDive splits the rules at the dive keyword into the rules of the field
+// and the rules of its elements
+func splitDive(rules []string) ([]string, []string) {
+	for i, rule := range rules {
+		if rule == "dive" {
+			return rules[:i], rules[i+1:]
+		}
+	}
+
+	return rules, nil
+}
+
+// applyRules maps the validation rules of a value of type t to schema keywords.
+// Rules without an equivalent keyword are ignored.
+func applyRules(schema map[string]interface{}, t Type, rules []string) {
+	for _, rule := range rules {
+		tag, param, _ := strings.Cut(rule, "=")
+
+		if format, ok := ruleFormats[tag]; ok {
+			schema["format"] = format
+			continue
+		}
+
+		switch tag {
+		case "gte", "min", "lte", "max", "gt", "lt", "len":
+			applyBound(schema, t, tag, param)
+		case "eq":
+			schema["const"] = scalarJSON(param, t)
+		case "oneof":
+			var values []interface{}
+			for _, value := range strings.Fields(param) {
+				values = append(values, scalarJSON(value, t))
+			}
+			schema["enum"] = values
+		}
+	}
+}
+
+// applyBound maps a bound rule such as gte=1 to schema keywords. Bounds with a
+// non numeric parameter, e.g. durations, and bounds of text values, which apply
+// to the decoded value rather than the text, have no equivalent and are ignored.
+func applyBound(schema map[string]interface{}, t Type, tag, param string) {
+	if _, err := strconv.ParseFloat(param, 64); err != nil || t.Kind == KindDuration || t.Kind == KindText {
+		return
+	}
+
+	limit := json.Number(param)
+	minKeyword := boundKeyword(t, "minimum", "minLength", "minItems", "minProperties")
+	maxKeyword := boundKeyword(t, "maximum", "maxLength", "maxItems", "maxProperties")
+
+	switch tag {
+	case "gte", "min":
+		schema[minKeyword] = limit
+	case "lte", "max":
+		schema[maxKeyword] = limit
+	case "len":
+		schema[minKeyword] = limit
+		schema[maxKeyword] = limit
+	case "gt":
+		if isNumeric(t) {
+			schema["exclusiveMinimum"] = limit
+		}
+	case "lt":
+		if isNumeric(t) {
+			schema["exclusiveMaximum"] = limit
+		}
+	}
+}
+
+// boundKeyword returns the keyword limiting a value of type t, which is the value
+// itself for numbers, the length for strings and the size of collections
+func boundKeyword(t Type, number, str, array, object string) string {
+	switch t.Kind {
+	case KindString, KindText:
+		return str
+	case KindSlice:
+		return array
+	case KindMap:
+		return object
+	default:
+		return number
+	}
+}
+
+func isNumeric(t Type) bool {
+	return t.Kind == KindInt || t.Kind == KindUint || t.Kind == KindFloat
+}
+
+// jsonNumber returns param as a JSON number, or as a string if it is not a number
+func jsonNumber(param string) interface{} {
+	if _, err := strconv.ParseFloat(param, 64); err != nil {
+		return param
+	}
+
+	return json.Number(param)
+}
+
+// scalarJSON converts a string value into the JSON value of type t
+func scalarJSON(value string, t Type) interface{} {
+	switch t.Kind {
+	case KindBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case KindInt, KindUint, KindFloat:
+		return jsonNumber(value)
+	}
+
+	return value
+}
+
+// defaultJSON converts a default tag into the JSON value of type t, parsing
+// slices and maps the same way as the env parser
+func defaultJSON(value string, t Type) interface{} {
+	switch t.Kind {
+	case KindSlice:
+		items := []interface{}{}
+		if value == "" {
+			return items
+		}
+
+		for _, item := range strings.Split(value, ",") {
+			items = append(items, scalarJSON(strings.TrimSpace(item), *t.Elem))
+		}
+
+		return items
+	case KindMap:
+		object := map[string]interface{}{}
+		for _, pair := range strings.Split(value, ",") {
+			k, v, ok := strings.Cut(pair, ":")
+			if ok {
+				object[strings.TrimSpace(k)] = scalarJSON(strings.TrimSpace(v), *t.Elem)
+			}
+		}
+
+		return object
+	default:
+		return scalarJSON(value, t)
+	}
+}
@@ -0,0 +1,95 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaConfig struct {
+	Env      string         `yaml:"env" validate:"required,oneof=dev prod"`
+	Port     int            `yaml:"port" default:"8080" validate:"required,gte=1024,lte=65535"`
+	Workers  uint           `yaml:"workers" validate:"gt=0,lt=100"`
+	Ratio    float64        `yaml:"ratio" validate:"eq=0.5"`
+	Name     string         `yaml:"name" desc:"Service name" validate:"min=3,max=20"`
+	Endpoint string         `yaml:"endpoint" validate:"url"`
+	Timeout  time.Duration  `yaml:"timeout" default:"30s" validate:"duration_range=1s:1m"`
+	Hosts    []string       `yaml:"hosts" default:"a,b" validate:"min=1,dive,hostname"`
+	Weights  map[string]int `yaml:"weights" default:"a:1"`
+	Token    string         `yaml:"token" default:"dev-token" secret:"true"`
+	Legacy   bool           `yaml:"legacy" default:"false" deprecated:""`
+	Skipped  string         `yaml:"-"`
+	Database struct {
+		Host string `yaml:"host" validate:"required"`
+	} `yaml:"database"`
+	Common struct {
+		Region string `yaml:"region" validate:"len=2"`
+	} `yaml:",inline"`
+	Extra map[string]interface{} `yaml:"extra"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	fields, err := FromValue(schemaConfig{})
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, WriteJSONSchema(&b, fields))
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["env"],
+		"properties": {
+			"env": {"type": "string", "enum": ["dev", "prod"]},
+			"port": {"type": "integer", "minimum": 1024, "maximum": 65535, "default": 8080},
+			"workers": {"type": "integer", "minimum": 0, "exclusiveMinimum": 0, "exclusiveMaximum": 100},
+			"ratio": {"type": "number", "const": 0.5},
+			"name": {"type": "string", "minLength": 3, "maxLength": 20, "description": "Service name"},
+			"endpoint": {"type": "string", "format": "uri"},
+			"timeout": {"type": "string", "pattern": "^[-+]?([0-9]+(\\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$", "default": "30s"},
+			"hosts": {"type": "array", "minItems": 1, "items": {"type": "string", "format": "hostname"}, "default": ["a", "b"]},
+			"weights": {"type": "object", "additionalProperties": {"type": "integer"}, "default": {"a": 1}},
+			"token": {"type": "string"},
+			"legacy": {"type": "boolean", "default": false, "deprecated": true},
+			"database": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["host"],
+				"properties": {"host": {"type": "string"}}
+			},
+			"region": {"type": "string", "minLength": 2, "maxLength": 2},
+			"extra": {"type": "object", "additionalProperties": {}}
+		}
+	}`, b.String())
+}
+
+func TestJSONSchemaOptional(t *testing.T) {
+	fields := []Field{{Name: "Retries", Type: Type{Name: "int", Kind: KindInt}, Optional: true}}
+
+	schema := JSONSchema(fields)
+	data, err := json.Marshal(schema["properties"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"retries": {"type": ["integer", "null"]}}`, string(data))
+}
+
+// byteSize is a named integer parsed from text such as 512MiB
+type byteSize uint64
+
+func (b *byteSize) UnmarshalText([]byte) error { return nil }
+
+func TestJSONSchemaText(t *testing.T) {
+	fields, err := FromValue(struct {
+		MaxUpload byteSize `yaml:"max_upload" default:"512MiB" validate:"gte=1024"`
+	}{})
+	require.NoError(t, err)
+	assert.Equal(t, KindText, fields[0].Type.Kind)
+
+	data, err := json.Marshal(JSONSchema(fields)["properties"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"max_upload": {"type": "string", "default": "512MiB"}}`, string(data))
+}
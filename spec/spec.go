@@ -0,0 +1,234 @@
+// Package spec describes configuration structs independent of their values. The
+// description is built from the struct tags used by goconf and drives the
+// generators of the goconf command, such as the .env.example generator.
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/wgarunap/goconf"
+)
+
+// Kind classifies the type of a configuration field
+type Kind string
+
+const (
+	// KindString is a string field
+	KindString Kind = "string"
+	// KindBool is a boolean field
+	KindBool Kind = "bool"
+	// KindInt is a signed integer field
+	KindInt Kind = "int"
+	// KindUint is an unsigned integer field
+	KindUint Kind = "uint"
+	// KindFloat is a floating point field
+	KindFloat Kind = "float"
+	// KindDuration is a time.Duration field such as "30s"
+	KindDuration Kind = "duration"
+	// KindText is a field parsed from text using encoding.TextUnmarshaler, e.g. time.Time
+	// or goconf.ByteSize
+	KindText Kind = "text"
+	// KindSlice is a slice or array field, comma separated in environment variables
+	KindSlice Kind = "slice"
+	// KindMap is a map field, comma separated key:value pairs in environment variables
+	KindMap Kind = "map"
+	// KindStruct is a nested struct with its own Fields
+	KindStruct Kind = "struct"
+	// KindOther is a field of any other type
+	KindOther Kind = "other"
+)
+
+// Type describes the Go type of a field
+type Type struct {
+	// Name is the Go type name, e.g. "time.Duration" or "[]string"
+	Name string
+	// Kind classifies the type
+	Kind Kind
+	// Elem is the element type of slices and maps
+	Elem *Type
+	// Key is the key type of maps
+	Key *Type
+}
+
+// Field describes a single configuration field
+type Field struct {
+	// Name is the Go field name
+	Name string
+	// Path is the Go field path from the root struct, e.g. ["Database", "Host"]
+	Path []string
+	// Type is the type of the field, the value type for goconf.Optional fields
+	Type Type
+	// Optional reports whether the field is a goconf.Optional
+	Optional bool
+	// Env is the environment variable of the field including the envPrefix of
+	// its parent structs. Fields without an env tag have the name derived from
+	// their path if enabled with goconf.SetEnvNaming, and no variable otherwise.
+	Env string
+	// YAML is the dotted YAML key path, e.g. "database.host", empty if the
+	// field is excluded with `yaml:"-"`
+	YAML string
+	// Default holds the `envDefault` or `default` tag of the field
+	Default string
+	// HasDefault reports whether the field declares a default value
+	HasDefault bool
+	// Secret reports whether the field is marked with `secret:"true"`
+	Secret bool
+	// Description holds the `desc` tag of the field
+	Description string
+	// Validate holds the `validate` tag of the field
+	Validate string
+	// Deprecated holds the `deprecated` tag of the field
+	Deprecated string
+	// Tags holds all struct tags of the field
+	Tags reflect.StructTag
+	// Fields holds the fields of a nested struct
+	Fields []Field
+}
+
+// IsStruct reports whether the field is a nested struct with its own Fields
+func (f Field) IsStruct() bool {
+	return f.Type.Kind == KindStruct
+}
+
+// IsDeprecated reports whether the field is marked with the `deprecated` tag
+func (f Field) IsDeprecated() bool {
+	_, ok := f.Tags.Lookup("deprecated")
+	return ok
+}
+
+// Rules returns the validation rules of the `validate` tag, e.g. ["required", "gte=1024"].
+// Commas inside a rule parameter cannot be told apart, so rules are split at every comma.
+func (f Field) Rules() []string {
+	if f.Validate == "" {
+		return nil
+	}
+
+	return strings.Split(f.Validate, ",")
+}
+
+// Required reports whether the field has the `required` validation rule
+func (f Field) Required() bool {
+	for _, rule := range f.Rules() {
+		if rule == "required" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Flatten returns the leaf fields of the tree in order, dropping nested struct nodes
+func Flatten(fields []Field) []Field {
+	var flat []Field
+
+	for _, f := range fields {
+		if f.IsStruct() {
+			flat = append(flat, Flatten(f.Fields)...)
+			continue
+		}
+
+		flat = append(flat, f)
+	}
+
+	return flat
+}
+
+// structField is a struct field independent of how its type was loaded
+type structField struct {
+	name      string
+	tag       reflect.StructTag
+	exported  bool
+	anonymous bool
+	typ       Type
+	optional  bool
+	// fields returns the fields of a nested struct
+	fields func() ([]structField, error)
+}
+
+// reflectField returns the reflect.StructField resolved by goconf.EnvPath, holding
+// the name and tags of the field
+func (sf structField) reflectField() reflect.StructField {
+	return reflect.StructField{Name: sf.name, Tag: sf.tag, Anonymous: sf.anonymous}
+}
+
+// build converts struct fields into Fields, resolving env names and YAML paths
+func build(fields []structField, path []string, env goconf.EnvPath, yamlPrefix string) ([]Field, error) {
+	result := make([]Field, 0, len(fields))
+
+	for _, sf := range fields {
+		if !sf.exported {
+			continue
+		}
+
+		f := Field{
+			Name:        sf.name,
+			Path:        append(append([]string(nil), path...), sf.name),
+			Type:        sf.typ,
+			Optional:    sf.optional,
+			Secret:      sf.tag.Get("secret") == "true",
+			Description: sf.tag.Get("desc"),
+			Validate:    sf.tag.Get("validate"),
+			Deprecated:  sf.tag.Get("deprecated"),
+			Tags:        sf.tag,
+		}
+
+		f.Default, f.HasDefault = sf.tag.Lookup("envDefault")
+		if !f.HasDefault {
+			f.Default, f.HasDefault = sf.tag.Lookup("default")
+		}
+
+		yamlName, inline := yamlKey(sf)
+		switch {
+		case yamlName == "-":
+		case inline:
+			f.YAML = yamlPrefix
+		case yamlPrefix != "":
+			f.YAML = yamlPrefix + "." + yamlName
+		default:
+			f.YAML = yamlName
+		}
+
+		if f.IsStruct() {
+			nested, err := sf.fields()
+			if err != nil {
+				return nil, err
+			}
+
+			nestedYAML := f.YAML
+			if yamlName == "-" {
+				nestedYAML = ""
+			}
+
+			f.Fields, err = build(nested, f.Path, env.Nested(sf.reflectField()), nestedYAML)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			f.Env, _ = env.Key(sf.reflectField())
+		}
+
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+// yamlKey returns the YAML key of a field and whether it is inlined into its parent.
+// Without a yaml tag the key is the lowercased field name.
+func yamlKey(sf structField) (string, bool) {
+	value := sf.tag.Get("yaml")
+	name, flags, _ := strings.Cut(value, ",")
+
+	if name == "" {
+		name = strings.ToLower(sf.name)
+	}
+
+	return name, strings.Contains(flags, "inline")
+}
+
+// errUnsupported is returned for root types that are not structs
+func errUnsupported(name string) error {
+	return fmt.Errorf("%s is not a struct", name)
+}
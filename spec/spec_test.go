@@ -0,0 +1,125 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wgarunap/goconf"
+)
+
+type testConfig struct {
+	Name     string               `env:"APP_NAME" yaml:"name" envDefault:"app" desc:"Application name" validate:"required,min=3"`
+	Timeout  time.Duration        `env:"TIMEOUT" default:"30s"`
+	Retries  goconf.Optional[int] `env:"RETRIES" yaml:"retries"`
+	Hosts    []string             `env:"HOSTS" yaml:"hosts"`
+	Labels   map[string]float64   `yaml:"labels"`
+	Started  time.Time            `yaml:"started"`
+	Ignored  string               `env:"-" yaml:"-"`
+	Legacy   string               `env:"LEGACY" deprecated:""`
+	Database struct {
+		Host     string `env:"HOST" yaml:"host"`
+		Password string `env:"PASSWORD" yaml:"password" secret:"true"`
+	} `yaml:"database" envPrefix:"DB_"`
+	Common struct {
+		Region string `env:"REGION" yaml:"region"`
+	} `yaml:",inline"`
+	internal string
+}
+
+func TestFromValue(t *testing.T) {
+	fields, err := FromValue(&testConfig{})
+	require.NoError(t, err)
+	require.Len(t, fields, 10)
+
+	name := fields[0]
+	assert.Equal(t, "APP_NAME", name.Env)
+	assert.Equal(t, "name", name.YAML)
+	assert.Equal(t, Type{Name: "string", Kind: KindString}, name.Type)
+	assert.Equal(t, "app", name.Default)
+	assert.True(t, name.HasDefault)
+	assert.Equal(t, "Application name", name.Description)
+	assert.Equal(t, []string{"required", "min=3"}, name.Rules())
+	assert.True(t, name.Required())
+
+	timeout := fields[1]
+	assert.Equal(t, "timeout", timeout.YAML)
+	assert.Equal(t, KindDuration, timeout.Type.Kind)
+	assert.Equal(t, "30s", timeout.Default)
+	assert.False(t, timeout.Required())
+
+	assert.True(t, fields[2].Optional)
+	assert.Equal(t, Type{Name: "int", Kind: KindInt}, fields[2].Type)
+
+	assert.Equal(t, Type{Name: "[]string", Kind: KindSlice, Elem: &Type{Name: "string", Kind: KindString}}, fields[3].Type)
+	assert.Equal(t, Type{
+		Name: "map[string]float64",
+		Kind: KindMap,
+		Key:  &Type{Name: "string", Kind: KindString},
+		Elem: &Type{Name: "float64", Kind: KindFloat},
+	}, fields[4].Type)
+	assert.Equal(t, KindText, fields[5].Type.Kind)
+
+	assert.Empty(t, fields[6].Env)
+	assert.Empty(t, fields[6].YAML)
+
+	assert.True(t, fields[7].IsDeprecated())
+	assert.False(t, fields[0].IsDeprecated())
+
+	database := fields[8]
+	assert.True(t, database.IsStruct())
+	assert.Empty(t, database.Env)
+	assert.Equal(t, "DB_PASSWORD", database.Fields[1].Env)
+	assert.Equal(t, "database.password", database.Fields[1].YAML)
+	assert.Equal(t, []string{"Database", "Password"}, database.Fields[1].Path)
+	assert.True(t, database.Fields[1].Secret)
+
+	assert.Equal(t, "region", fields[9].Fields[0].YAML)
+
+	assert.Len(t, Flatten(fields), 11)
+}
+
+func TestFromType(t *testing.T) {
+	fields, err := FromType(reflect.TypeOf(testConfig{}))
+	require.NoError(t, err)
+	assert.Len(t, fields, 10)
+
+	_, err = FromValue("config")
+	assert.EqualError(t, err, "string is not a struct")
+
+	_, err = FromValue(nil)
+	assert.EqualError(t, err, "<nil> is not a struct")
+}
+
+func TestFromValueDerivedEnv(t *testing.T) {
+	goconf.SetEnvNaming(goconf.EnvNaming{Derive: true, Prefix: "APP_"})
+	defer goconf.SetEnvNaming(goconf.EnvNaming{})
+
+	type Embedded struct {
+		Region string
+	}
+
+	fields, err := FromValue(struct {
+		PortA    int
+		Name     string `env:"NAME"`
+		Ignored  string `env:"-"`
+		Database struct {
+			MaxIdleConns int
+		}
+		Cache struct {
+			Host string
+		} `envPrefix:"REDIS_"`
+		Embedded
+	}{})
+	require.NoError(t, err)
+
+	var envs []string
+	for _, f := range Flatten(fields) {
+		envs = append(envs, f.Env)
+	}
+
+	assert.Equal(t, []string{"APP_PORT_A", "NAME", "", "APP_DATABASE_MAX_IDLE_CONNS", "APP_REDIS_HOST", "APP_REGION"}, envs)
+}
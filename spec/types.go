@@ -0,0 +1,178 @@
+package spec
+
+import (
+	"go/types"
+	"reflect"
+
+	"github.com/wgarunap/goconf"
+)
+
+// FromTypes describes the fields of a struct loaded with go/types, e.g. using
+// golang.org/x/tools/go/packages, so that the struct can be described without
+// building and running the program that declares it. t must be a struct, a named
+// struct or a pointer to either.
+func FromTypes(t types.Type) ([]Field, error) {
+	for {
+		ptr, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = ptr.Elem()
+	}
+
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil, errUnsupported(typeName(t))
+	}
+
+	return build(typesFields(s), nil, goconf.RootEnvPath(), "")
+}
+
+func typesFields(s *types.Struct) []structField {
+	fields := make([]structField, 0, s.NumFields())
+
+	for i := 0; i < s.NumFields(); i++ {
+		v := s.Field(i)
+		fieldType := v.Type()
+
+		optional := false
+		if elem, ok := typesOptional(fieldType); ok {
+			fieldType = elem
+			optional = true
+		}
+
+		nested := fieldType
+		for {
+			ptr, ok := nested.Underlying().(*types.Pointer)
+			if !ok {
+				break
+			}
+			nested = ptr.Elem()
+		}
+
+		fields = append(fields, structField{
+			name:      v.Name(),
+			tag:       reflect.StructTag(s.Tag(i)),
+			exported:  v.Exported(),
+			anonymous: v.Embedded(),
+			typ:       typesType(fieldType),
+			optional:  optional,
+			fields: func() ([]structField, error) {
+				st, ok := nested.Underlying().(*types.Struct)
+				if !ok {
+					return nil, errUnsupported(typeName(nested))
+				}
+
+				return typesFields(st), nil
+			},
+		})
+	}
+
+	return fields
+}
+
+// typesType describes a go/types type
+func typesType(t types.Type) Type {
+	result := Type{Name: typeName(t)}
+
+	for {
+		ptr, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = ptr.Elem()
+	}
+
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration" {
+			result.Kind = KindDuration
+			return result
+		}
+
+		if !isString(t) && hasUnmarshalText(t) {
+			result.Kind = KindText
+			return result
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		result.Kind = basicKind(u)
+	case *types.Slice:
+		elem := typesType(u.Elem())
+		result.Kind = KindSlice
+		result.Elem = &elem
+	case *types.Array:
+		elem := typesType(u.Elem())
+		result.Kind = KindSlice
+		result.Elem = &elem
+	case *types.Map:
+		key, elem := typesType(u.Key()), typesType(u.Elem())
+		result.Kind = KindMap
+		result.Key = &key
+		result.Elem = &elem
+	case *types.Struct:
+		result.Kind = KindStruct
+	default:
+		result.Kind = KindOther
+	}
+
+	return result
+}
+
+func basicKind(b *types.Basic) Kind {
+	info := b.Info()
+
+	switch {
+	case info&types.IsString != 0:
+		return KindString
+	case info&types.IsBoolean != 0:
+		return KindBool
+	case info&types.IsUnsigned != 0:
+		return KindUint
+	case info&types.IsInteger != 0:
+		return KindInt
+	case info&types.IsFloat != 0:
+		return KindFloat
+	default:
+		return KindOther
+	}
+}
+
+// isString reports whether the underlying type of t is a string
+func isString(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// hasUnmarshalText reports whether *t implements encoding.TextUnmarshaler
+func hasUnmarshalText(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, "UnmarshalText")
+	_, ok := obj.(*types.Func)
+
+	return ok
+}
+
+// typesOptional returns the value type if t is an instance of goconf.Optional
+func typesOptional(t types.Type) (types.Type, bool) {
+	named, ok := t.(*types.Named)
+	if !ok || named.TypeArgs().Len() != 1 {
+		return nil, false
+	}
+
+	obj := named.Origin().Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != optionalPkgPath || obj.Name() != "Optional" {
+		return nil, false
+	}
+
+	return named.TypeArgs().At(0), true
+}
+
+// typeName formats t using package names instead of package paths, e.g. time.Duration
+func typeName(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		return p.Name()
+	})
+}
@@ -0,0 +1,273 @@
+package goconf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+)
+
+// DefaultLocale is the locale used for validation messages unless SetLocale is called
+const DefaultLocale = "en"
+
+// TranslationRegisterFunc registers the validation messages of a locale on a validator,
+// e.g. the RegisterDefaultTranslations functions of the
+// "github.com/go-playground/validator/v10/translations/..." packages
+type TranslationRegisterFunc func(v *validator.Validate, trans ut.Translator) error
+
+// localeRegistration holds everything needed to set up a locale on a Validator
+type localeRegistration struct {
+	translator locales.Translator
+	register   TranslationRegisterFunc
+}
+
+var (
+	localesMu         sync.RWMutex
+	registeredLocales = map[string]localeRegistration{
+		DefaultLocale: {translator: en.New(), register: registerEnglishTranslations},
+	}
+)
+
+// RegisterLocale makes a locale available for validation messages. The locale is
+// identified by the translator's locale name, e.g. "fr" for locales/fr.
+//
+// Usage Example:
+//
+//	import (
+//	    "github.com/go-playground/locales/fr"
+//	    frtranslations "github.com/go-playground/validator/v10/translations/fr"
+//	)
+//
+//	goconf.RegisterLocale(fr.New(), frtranslations.RegisterDefaultTranslations)
+//	if err := goconf.SetLocale("fr"); err != nil {
+//	    // Handle unknown locale
+//	}
+func RegisterLocale(translator locales.Translator, register TranslationRegisterFunc) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+
+	registeredLocales[translator.Locale()] = localeRegistration{translator: translator, register: register}
+}
+
+// SetLocale selects the locale used for the messages of this Validator.
+// The locale must be registered using RegisterLocale, "en" is always available.
+func (v *Validator) SetLocale(locale string) error {
+	trans, err := v.translatorFor(locale)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.translator = trans
+
+	return nil
+}
+
+// translatorFor returns the translator of a registered locale, registering its
+// messages on the Validator on first use
+func (v *Validator) translatorFor(locale string) (ut.Translator, error) {
+	localesMu.RLock()
+	registration, ok := registeredLocales[locale]
+	localesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("locale %s is not registered", locale)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if trans, ok := v.translators[locale]; ok {
+		return trans, nil
+	}
+
+	trans, _ := ut.New(registration.translator, registration.translator).GetTranslator(locale)
+	if err := registration.register(v.validate, trans); err != nil {
+		return nil, fmt.Errorf("failed to register %s translations: %w", locale, err)
+	}
+
+	if v.translators == nil {
+		v.translators = make(map[string]ut.Translator)
+	}
+
+	v.translators[locale] = trans
+
+	return trans, nil
+}
+
+// Translate returns the localized message of every validation failure in err,
+// keyed by the field namespace such as Config.Database.Port. It returns nil if
+// err is not a validator.ValidationErrors.
+func (v *Validator) Translate(err error) map[string]string {
+	return v.translate(err, "")
+}
+
+// translate implements Translate with the messages of locale, or of the
+// selected locale of the Validator if locale is empty
+func (v *Validator) translate(err error, locale string) map[string]string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	trans := v.localeTranslator(locale)
+
+	messages := make(map[string]string, len(validationErrors))
+	for _, fe := range validationErrors {
+		messages[fe.Namespace()] = fe.Translate(trans)
+	}
+
+	return messages
+}
+
+// FormatError renders err as a human readable message with one localized line per
+// validation failure. Errors that are not validation errors are returned as is.
+//
+// Example output:
+//
+//	validation failed:
+//	  - Config.Port: Port must be 1,024 or greater
+//	  - Config.Name: Name is a required field
+func (v *Validator) FormatError(err error) string {
+	return v.formatError(err, "")
+}
+
+// formatError implements FormatError with the messages of locale, or of the
+// selected locale of the Validator if locale is empty
+func (v *Validator) formatError(err error, locale string) string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err.Error()
+	}
+
+	trans := v.localeTranslator(locale)
+
+	var b strings.Builder
+	b.WriteString("validation failed:")
+
+	for _, fe := range validationErrors {
+		fmt.Fprintf(&b, "\n  - %s: %s", fe.Namespace(), fe.Translate(trans))
+	}
+
+	return b.String()
+}
+
+// localeTranslator returns the translator of locale, falling back to the selected
+// locale of the Validator if locale is empty or not registered
+func (v *Validator) localeTranslator(locale string) ut.Translator {
+	if locale != "" {
+		if trans, err := v.translatorFor(locale); err == nil {
+			return trans
+		}
+	}
+
+	return v.currentTranslator()
+}
+
+// currentTranslator returns the translator of the selected locale, setting up
+// the default locale on first use
+func (v *Validator) currentTranslator() ut.Translator {
+	v.mu.RLock()
+	trans := v.translator
+	v.mu.RUnlock()
+
+	if trans != nil {
+		return trans
+	}
+
+	if err := v.SetLocale(DefaultLocale); err != nil {
+		panic(fmt.Sprintf("goconf: failed to set up default locale: %v", err))
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.translator
+}
+
+// SetLocale selects the locale of the validation messages returned by FormatValidationError
+func SetLocale(locale string) error {
+	return defaultValidator.SetLocale(locale)
+}
+
+// FormatValidationError renders an error returned by StructValidator as a human
+// readable, localized message, see Validator.FormatError
+func FormatValidationError(err error) string {
+	return defaultValidator.FormatError(err)
+}
+
+// builtinRuleMessages holds the English messages of the goconf configuration rules
+var builtinRuleMessages = map[string]string{
+	"port":           "{0} must be a valid port number",
+	"hostport":       "{0} must be a valid host:port address",
+	"duration_range": "{0} must be a duration {1}",
+	"file_exists":    "{0} must be the path of an existing file",
+	"dir_writable":   "{0} must be the path of a writable directory",
+	"cidr_list":      "{0} must be a list of valid CIDR networks",
+	"crontab":        "{0} must be a valid crontab expression",
+	"regexp":         "{0} must be a valid regular expression",
+	"tz":             "{0} must be a valid time zone",
+	"pem_cert":       "{0} must be a PEM encoded certificate",
+	"pem_key":        "{0} must be a PEM encoded private key",
+	"dsn":            "{0} must be a valid {1} connection string",
+	"loglevel":       "{0} must be a valid log level",
+}
+
+// registerEnglishTranslations registers the validator's English messages and
+// the messages of the goconf configuration rules
+func registerEnglishTranslations(v *validator.Validate, trans ut.Translator) error {
+	if err := entranslations.RegisterDefaultTranslations(v, trans); err != nil {
+		return err
+	}
+
+	for tag, message := range builtinRuleMessages {
+		err := v.RegisterTranslation(tag, trans,
+			func(trans ut.Translator) error {
+				return trans.Add(tag, message, true)
+			},
+			func(trans ut.Translator, fe validator.FieldError) string {
+				msg, err := trans.T(tag, fe.Field(), builtinRuleParam(tag, fe.Param()))
+				if err != nil {
+					return fe.Error()
+				}
+
+				return msg
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// builtinRuleParam formats the parameter of a goconf configuration rule for its message
+func builtinRuleParam(tag, param string) string {
+	switch tag {
+	case "duration_range":
+		minParam, maxParam, _ := strings.Cut(param, ":")
+		switch {
+		case minParam == "":
+			return "of at most " + maxParam
+		case maxParam == "":
+			return "of at least " + minParam
+		default:
+			return "between " + minParam + " and " + maxParam
+		}
+	case "dsn":
+		if param == "" {
+			return "database"
+		}
+	}
+
+	return param
+}
@@ -0,0 +1,70 @@
+package goconf
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales/fr"
+	frtranslations "github.com/go-playground/validator/v10/translations/fr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type translationConfig struct {
+	Name    string        `validate:"required"`
+	Port    int           `validate:"gte=1024"`
+	Timeout time.Duration `validate:"duration_range=1s:5m"`
+	Level   string        `validate:"loglevel"`
+}
+
+func TestFormatError(t *testing.T) {
+	v := NewValidator()
+
+	err := v.Struct(translationConfig{Port: 80, Timeout: time.Hour, Level: "verbose"})
+	require.Error(t, err)
+
+	assert.Equal(t, `validation failed:
+  - translationConfig.Name: Name is a required field
+  - translationConfig.Port: Port must be 1,024 or greater
+  - translationConfig.Timeout: Timeout must be a duration between 1s and 5m
+  - translationConfig.Level: Level must be a valid log level`, v.FormatError(err))
+
+	assert.Equal(t, "boom", v.FormatError(errors.New("boom")))
+}
+
+func TestTranslate(t *testing.T) {
+	v := NewValidator()
+
+	err := v.Struct(translationConfig{Name: "app", Port: 8080, Timeout: time.Second, Level: "loud"})
+	require.Error(t, err)
+
+	assert.Equal(t, map[string]string{
+		"translationConfig.Level": "Level must be a valid log level",
+	}, v.Translate(err))
+	assert.Nil(t, v.Translate(errors.New("boom")))
+}
+
+func TestSetLocale(t *testing.T) {
+	RegisterLocale(fr.New(), frtranslations.RegisterDefaultTranslations)
+
+	v := NewValidator()
+	require.NoError(t, v.SetLocale("fr"))
+
+	err := v.Struct(translationConfig{Port: 8080, Timeout: time.Second, Level: "info"})
+	require.Error(t, err)
+	assert.Equal(t, "validation failed:\n  - translationConfig.Name: Name est un champ obligatoire", v.FormatError(err))
+
+	require.NoError(t, v.SetLocale(DefaultLocale))
+	assert.Equal(t, "validation failed:\n  - translationConfig.Name: Name is a required field", v.FormatError(err))
+
+	require.ErrorContains(t, v.SetLocale("xx"), "locale xx is not registered")
+}
+
+func TestBuiltinRuleParam(t *testing.T) {
+	assert.Equal(t, "between 1s and 5m", builtinRuleParam("duration_range", "1s:5m"))
+	assert.Equal(t, "of at least 1s", builtinRuleParam("duration_range", "1s:"))
+	assert.Equal(t, "of at most 5m", builtinRuleParam("duration_range", ":5m"))
+	assert.Equal(t, "database", builtinRuleParam("dsn", ""))
+	assert.Equal(t, "postgres", builtinRuleParam("dsn", "postgres"))
+}
@@ -2,10 +2,161 @@ package goconf
 
 import (
 	"errors"
+	"reflect"
+	"sync"
 
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 )
 
+// Validator validates configuration structs against the rules defined using struct tags.
+// It caches struct metadata between calls, so repeated validation in reload loops
+// is cheap, and allows registering custom rules, struct level validations and aliases.
+// A Validator is safe for concurrent use.
+//
+// StructValidator uses a shared default Validator, use NewValidator to create an
+// independent instance with its own rules.
+type Validator struct {
+	mu          sync.RWMutex
+	validate    *validator.Validate
+	translator  ut.Translator
+	translators map[string]ut.Translator
+	// scanned holds the types already checked for Optional fields
+	scanned map[reflect.Type]bool
+	// customized reports whether rules, struct level validations or aliases were registered
+	customized bool
+}
+
+// NewValidator creates a Validator with the `WithRequiredStructEnabled` option
+// and the goconf configuration rules such as port, hostport and duration_range
+func NewValidator() *Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	registerBuiltinRules(v)
+	v.RegisterCustomTypeFunc(validatedValue, valueTypes...)
+
+	return &Validator{
+		validate: v,
+	}
+}
+
+var defaultValidator = NewValidator()
+
+// DefaultValidator returns the Validator used by StructValidator
+func DefaultValidator() *Validator {
+	return defaultValidator
+}
+
+// RegisterValidation adds a validation rule with the given tag.
+//
+// Usage Example:
+//
+//	err := v.RegisterValidation("even", func(fl validator.FieldLevel) bool {
+//	    return fl.Field().Int()%2 == 0
+//	})
+func (v *Validator) RegisterValidation(tag string, fn validator.Func) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.customized = true
+
+	return v.validate.RegisterValidation(tag, fn)
+}
+
+// RegisterStructValidation registers a struct level validation function for the
+// types of the given values, e.g. to validate fields that depend on each other.
+func (v *Validator) RegisterStructValidation(fn validator.StructLevelFunc, types ...interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.customized = true
+
+	v.validate.RegisterStructValidation(fn, types...)
+}
+
+// RegisterAlias registers an alias for one or more validation tags,
+// e.g. RegisterAlias("port", "gte=1,lte=65535") makes `validate:"port"` usable.
+func (v *Validator) RegisterAlias(alias, tags string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.customized = true
+
+	v.validate.RegisterAlias(alias, tags)
+}
+
+// IsCustomized reports whether rules, struct level validations or aliases were
+// registered on the Validator, which may change the meaning of the builtin rules.
+// The code generated by goconf gen only validates without reflection if not.
+func (v *Validator) IsCustomized() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.customized
+}
+
+// Var validates a single value against the given validation tags, e.g. "gte=1,lte=100"
+func (v *Validator) Var(value interface{}, tags string) error {
+	v.registerOptionalTypes(reflect.TypeOf(value))
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.validate.Var(value, tags)
+}
+
+// Struct validates a struct's fields, see StructValidator for details
+func (v *Validator) Struct(config interface{}) error {
+	v.registerOptionalTypes(reflect.TypeOf(config))
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	err := v.validate.Struct(config)
+
+	if err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return validationErrors
+		}
+
+		return errors.Join(err, errors.New("validation failed"))
+	}
+
+	return nil
+}
+
+// registerOptionalTypes registers the validation of the Optional types used
+// within t. Validating an Optional validates its value like a pointer.
+func (v *Validator) registerOptionalTypes(t reflect.Type) {
+	if t == nil {
+		return
+	}
+
+	v.mu.RLock()
+	scanned := v.scanned[t]
+	v.mu.RUnlock()
+
+	if scanned {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.scanned == nil {
+		v.scanned = make(map[reflect.Type]bool)
+	}
+
+	for _, optionalType := range optionalTypes(t) {
+		if !v.scanned[optionalType] {
+			v.validate.RegisterCustomTypeFunc(optionalValidationValue, reflect.Zero(optionalType).Interface())
+			v.scanned[optionalType] = true
+		}
+	}
+
+	v.scanned[t] = true
+}
+
 // StructValidator validates a struct's fields against the validation
 // rules defined using struct tags. It utilizes the "github.com/go-playground/validator/v10"
 // package for validation.
@@ -38,21 +189,27 @@ import (
 // Note:
 //   - The validator is initialized with the `WithRequiredStructEnabled` option, which ensures
 //     that nil struct fields are treated as invalid.
+//   - The validator is created once and reused, custom rules can be added using
+//     RegisterValidation, RegisterStructValidation and RegisterAlias.
 //   - The function will panic if the `config` parameter is not a struct or pointer to a struct.
 //
 // More validator information https://github.com/go-playground/validator
 func StructValidator(config interface{}) error {
-	v := validator.New(validator.WithRequiredStructEnabled())
-	err := v.Struct(config)
+	return defaultValidator.Struct(config)
+}
 
-	if err != nil {
-		var validationErrors validator.ValidationErrors
-		if errors.As(err, &validationErrors) {
-			return validationErrors
-		}
+// RegisterValidation adds a validation rule to the validator used by StructValidator
+func RegisterValidation(tag string, fn validator.Func) error {
+	return defaultValidator.RegisterValidation(tag, fn)
+}
 
-		return errors.Join(err, errors.New("validation failed"))
-	}
+// RegisterStructValidation registers a struct level validation function on the
+// validator used by StructValidator
+func RegisterStructValidation(fn validator.StructLevelFunc, types ...interface{}) {
+	defaultValidator.RegisterStructValidation(fn, types...)
+}
 
-	return nil
+// RegisterAlias registers a validation tag alias on the validator used by StructValidator
+func RegisterAlias(alias, tags string) {
+	defaultValidator.RegisterAlias(alias, tags)
 }
@@ -3,6 +3,8 @@ package goconf
 import (
 	"testing"
 
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,3 +52,109 @@ func TestStructValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorCustomRules(t *testing.T) {
+	v := NewValidator()
+
+	require.NoError(t, v.RegisterValidation("even", func(fl validator.FieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}))
+	v.RegisterAlias("listen_port", "gte=1,lte=65535")
+
+	type config struct {
+		Workers int `validate:"even"`
+		Port    int `validate:"listen_port"`
+	}
+
+	tests := map[string]struct {
+		config      config
+		expectedErr string
+	}{
+		"custom rule and alias pass": {
+			config: config{Workers: 4, Port: 8080},
+		},
+		"custom rule failed": {
+			config:      config{Workers: 3, Port: 8080},
+			expectedErr: "'Workers' failed on the 'even' tag",
+		},
+		"alias failed": {
+			config:      config{Workers: 2, Port: 70000},
+			expectedErr: "'Port' failed on the 'listen_port' tag",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := v.Struct(test.config)
+			if test.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+
+	assert.Panics(t, func() { _ = NewValidator().Struct(config{}) }, "rules are registered per validator")
+}
+
+func TestValidatorStructValidation(t *testing.T) {
+	type pool struct {
+		MinConns int
+		MaxConns int
+	}
+
+	v := NewValidator()
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		p := sl.Current().Interface().(pool)
+		if p.MinConns > p.MaxConns {
+			sl.ReportError(p.MinConns, "MinConns", "MinConns", "ltefield", "MaxConns")
+		}
+	}, pool{})
+
+	require.NoError(t, v.Struct(pool{MinConns: 1, MaxConns: 10}))
+	require.ErrorContains(t, v.Struct(pool{MinConns: 10, MaxConns: 1}), "'MinConns' failed on the 'ltefield' tag")
+}
+
+func TestDefaultValidator(t *testing.T) {
+	assert.Same(t, DefaultValidator(), DefaultValidator())
+
+	require.NoError(t, RegisterValidation("goconf_test_never", func(validator.FieldLevel) bool { return false }))
+
+	type config struct {
+		Name string `validate:"goconf_test_never"`
+	}
+
+	require.ErrorContains(t, StructValidator(config{}), "'Name' failed on the 'goconf_test_never' tag")
+}
+
+func BenchmarkStructValidator(b *testing.B) {
+	type config struct {
+		Name string `validate:"required"`
+		Age  int    `validate:"gte=0,lte=130"`
+	}
+
+	cfg := config{Name: "name", Age: 30}
+
+	for i := 0; i < b.N; i++ {
+		if err := StructValidator(cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestValidatorIsCustomized(t *testing.T) {
+	v := NewValidator()
+	assert.False(t, v.IsCustomized())
+
+	v.RegisterAlias("port", "gte=1,lte=65535")
+	assert.True(t, v.IsCustomized())
+
+	v = NewValidator()
+	v.RegisterStructValidation(func(validator.StructLevel) {}, struct{}{})
+	assert.True(t, v.IsCustomized())
+
+	v = NewValidator()
+	require.NoError(t, v.RegisterValidation("even", func(validator.FieldLevel) bool { return true }))
+	assert.True(t, v.IsCustomized())
+}
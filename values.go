@@ -0,0 +1,536 @@
+package goconf
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The value types below are decoded from text with UnmarshalText, so ParseEnv,
+// ParseYaml and the `default` tag accept the same input. They are printed and
+// re-emitted as the same text, e.g. 512MiB rather than 536870912.
+
+// ByteSize is a size in bytes read from text such as "512MiB", "1.5GB" or "1024".
+// Decimal units (kB, MB, GB, TB, PB, EB) are powers of 1000, binary units (KiB,
+// MiB, GiB, TiB, PiB, EiB) powers of 1024, and units are case insensitive.
+// Validation rules compare the number of bytes, e.g. `validate:"gte=1048576"`.
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    MaxUpload goconf.ByteSize `env:"MAX_UPLOAD" yaml:"max_upload" default:"32MiB"`
+//	}
+//
+//	http.MaxBytesReader(w, r.Body, int64(cfg.MaxUpload))
+type ByteSize uint64
+
+// Units of ByteSize
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+	EB ByteSize = 1000 * PB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+	EiB ByteSize = 1024 * PiB
+)
+
+// byteUnits are the units of ByteSize from the largest to the smallest
+var byteUnits = []struct {
+	name string
+	size ByteSize
+}{
+	{"EiB", EiB}, {"EB", EB}, {"PiB", PiB}, {"PB", PB}, {"TiB", TiB}, {"TB", TB},
+	{"GiB", GiB}, {"GB", GB}, {"MiB", MiB}, {"MB", MB}, {"KiB", KiB}, {"kB", KB}, {"B", Byte},
+}
+
+// String formats the size with the largest unit that represents it exactly
+func (b ByteSize) String() string {
+	for _, unit := range byteUnits {
+		if b != 0 && b%unit.size == 0 {
+			return strconv.FormatUint(uint64(b/unit.size), 10) + unit.name
+		}
+	}
+
+	return "0B"
+}
+
+// MarshalText returns the size formatted by String
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText parses a number of bytes followed by an optional unit
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(s)
+	}
+
+	number, unitName := s[:i], strings.TrimSpace(s[i:])
+
+	unit := Byte
+	if unitName != "" {
+		found := false
+		for _, u := range byteUnits {
+			if strings.EqualFold(u.name, unitName) {
+				unit, found = u.size, true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("invalid byte size %q: unknown unit %q", s, unitName)
+		}
+	}
+
+	size, ok := new(big.Rat).SetString(number)
+	if number == "" || !ok {
+		return fmt.Errorf("invalid byte size %q", s)
+	}
+
+	size.Mul(size, new(big.Rat).SetInt(new(big.Int).SetUint64(uint64(unit))))
+	if !size.IsInt() {
+		return fmt.Errorf("invalid byte size %q: not a whole number of bytes", s)
+	}
+
+	if !size.Num().IsUint64() {
+		return fmt.Errorf("invalid byte size %q: out of range", s)
+	}
+
+	*b = ByteSize(size.Num().Uint64())
+
+	return nil
+}
+
+// Duration is a time.Duration that also accepts days and weeks, e.g. "7d", "1d12h"
+// or "2w", besides the units of time.ParseDuration. A day is always 24 hours. It is
+// printed with days, e.g. 1d12h. Validation rules compare it like a time.Duration,
+// e.g. `validate:"gte=1h"`.
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    Retention goconf.Duration `env:"RETENTION" yaml:"retention" default:"30d"`
+//	}
+//
+//	cutoff := time.Now().Add(-cfg.Retention.Duration())
+type Duration time.Duration
+
+const day = 24 * time.Hour
+
+// Duration returns d as a time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String formats the duration with days followed by time.Duration units, leaving
+// out zero minutes and seconds, e.g. 7d, 1d12h or 1h30m
+func (d Duration) String() string {
+	v := time.Duration(d)
+	if v == math.MinInt64 {
+		return v.String()
+	}
+
+	sign := ""
+	if v < 0 {
+		sign, v = "-", -v
+	}
+
+	days, rest := v/day, v%day
+	if days == 0 {
+		return sign + trimDuration(rest)
+	}
+
+	s := sign + strconv.FormatInt(int64(days), 10) + "d"
+	if rest != 0 {
+		s += trimDuration(rest)
+	}
+
+	return s
+}
+
+// trimDuration formats d without zero minutes and seconds, e.g. 1h instead of 1h0m0s
+func trimDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+
+	return s
+}
+
+// MarshalText returns the duration formatted by String
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a duration such as "1d12h", see Duration
+func (d *Duration) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+
+	parsed, err := parseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
+
+// parseDuration parses a signed sequence of numbers and units like time.ParseDuration,
+// with the additional units d for days and w for weeks
+func parseDuration(s string) (time.Duration, error) {
+	// A single leading sign is allowed, a remaining one fails as a missing number
+	negative := strings.HasPrefix(s, "-")
+	if negative || strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+
+	if s == "0" {
+		return 0, nil
+	}
+
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total time.Duration
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if i <= 0 {
+			return 0, fmt.Errorf("missing number")
+		}
+
+		j := strings.IndexFunc(s[i:], func(r rune) bool { return (r >= '0' && r <= '9') || r == '.' })
+		if j < 0 {
+			j = len(s) - i
+		}
+
+		number, unit := s[:i], s[i:i+j]
+		s = s[i+j:]
+
+		var d time.Duration
+		var err error
+
+		switch unit {
+		case "d":
+			d, err = scaleHours(number, 24)
+		case "w":
+			d, err = scaleHours(number, 7*24)
+		default:
+			d, err = time.ParseDuration(number + unit)
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if total > math.MaxInt64-d {
+			return 0, fmt.Errorf("duration out of range")
+		}
+
+		total += d
+	}
+
+	if negative {
+		total = -total
+	}
+
+	return total, nil
+}
+
+// scaleHours returns number of hours multiplied by factor
+func scaleHours(number string, factor time.Duration) (time.Duration, error) {
+	hours, err := time.ParseDuration(number + "h")
+	if err != nil {
+		return 0, err
+	}
+
+	if hours > math.MaxInt64/factor {
+		return 0, fmt.Errorf("duration out of range")
+	}
+
+	return hours * factor, nil
+}
+
+// URL is a URL with a scheme, such as "https://example.com/api" or
+// "postgres://user:pass@db:5432/app". Mark URLs holding credentials as secret.
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    API goconf.URL `env:"API_URL" yaml:"api_url" validate:"required"`
+//	}
+//
+//	client.BaseURL = cfg.API.JoinPath("v1")
+type URL struct {
+	url.URL
+}
+
+// String returns the URL, or an empty string for the zero URL
+func (u URL) String() string {
+	if u.URL == (url.URL{}) {
+		return ""
+	}
+
+	return u.URL.String()
+}
+
+// MarshalText returns the URL formatted by String
+func (u URL) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText parses a URL with a scheme, empty text is the zero URL
+func (u *URL) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*u = URL{}
+		return nil
+	}
+
+	parsed, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", s, err)
+	}
+
+	if parsed.Scheme == "" {
+		return fmt.Errorf("invalid URL %q: missing scheme", s)
+	}
+
+	u.URL = *parsed
+
+	return nil
+}
+
+// IP is an IPv4 or IPv6 address such as "10.0.0.1" or "::1"
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    BindIP goconf.IP `env:"BIND_IP" yaml:"bind_ip" default:"0.0.0.0"`
+//	}
+//
+//	addr := netip.AddrPortFrom(cfg.BindIP.Addr, 8080)
+type IP struct {
+	netip.Addr
+}
+
+// String returns the address, or an empty string for the zero IP
+func (ip IP) String() string {
+	if !ip.IsValid() {
+		return ""
+	}
+
+	return ip.Addr.String()
+}
+
+// MarshalText returns the address formatted by String
+func (ip IP) MarshalText() ([]byte, error) {
+	return []byte(ip.String()), nil
+}
+
+// UnmarshalText parses an IP address, empty text is the zero IP
+func (ip *IP) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*ip = IP{}
+		return nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return fmt.Errorf("invalid IP address %q", s)
+	}
+
+	ip.Addr = addr
+
+	return nil
+}
+
+// CIDR is an IP network in CIDR notation such as "10.0.0.0/8" or "fd00::/64"
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    TrustedProxies []goconf.CIDR `env:"TRUSTED_PROXIES" yaml:"trusted_proxies"`
+//	}
+//
+//	trusted := cfg.TrustedProxies[0].Contains(addr)
+type CIDR struct {
+	netip.Prefix
+}
+
+// String returns the network, or an empty string for the zero CIDR
+func (c CIDR) String() string {
+	if !c.IsValid() {
+		return ""
+	}
+
+	return c.Prefix.String()
+}
+
+// MarshalText returns the network formatted by String
+func (c CIDR) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText parses a network in CIDR notation, empty text is the zero CIDR
+func (c *CIDR) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*c = CIDR{}
+		return nil
+	}
+
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR %q", s)
+	}
+
+	c.Prefix = prefix
+
+	return nil
+}
+
+// Regexp is a regular expression compiled with regexp.Compile when it is parsed.
+// The zero Regexp holds no expression.
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    AllowedOrigins goconf.Regexp `env:"ALLOWED_ORIGINS" yaml:"allowed_origins" default:"^https://.*\\.example\\.com$"`
+//	}
+//
+//	if cfg.AllowedOrigins.MatchString(origin) {
+//	    // ...
+//	}
+type Regexp struct {
+	*regexp.Regexp
+}
+
+// String returns the source text of the expression, or an empty string for the zero Regexp
+func (r Regexp) String() string {
+	if r.Regexp == nil {
+		return ""
+	}
+
+	return r.Regexp.String()
+}
+
+// MarshalText returns the source text of the expression
+func (r Regexp) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText compiles the expression, empty text is the zero Regexp
+func (r *Regexp) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		r.Regexp = nil
+		return nil
+	}
+
+	re, err := regexp.Compile(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid regular expression %q: %w", text, err)
+	}
+
+	r.Regexp = re
+
+	return nil
+}
+
+// Level is a log level read from debug, info, warn or error, case insensitive and
+// optionally with an offset such as info+2, with the values of slog.Level. The zero
+// Level is info. Validation rules compare its name, e.g. `validate:"oneof=info warn"`.
+//
+// Usage Example:
+//
+//	type Config struct {
+//	    LogLevel goconf.Level `env:"LOG_LEVEL" yaml:"log_level" default:"info"`
+//	}
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: cfg.LogLevel.Slog()}))
+type Level int
+
+// Levels of Level
+const (
+	LevelDebug Level = Level(slog.LevelDebug)
+	LevelInfo  Level = Level(slog.LevelInfo)
+	LevelWarn  Level = Level(slog.LevelWarn)
+	LevelError Level = Level(slog.LevelError)
+)
+
+// Slog returns the level as a slog.Level
+func (l Level) Slog() slog.Level {
+	return slog.Level(l)
+}
+
+// String returns the lowercase name of the level, e.g. info or debug+2
+func (l Level) String() string {
+	return strings.ToLower(slog.Level(l).String())
+}
+
+// MarshalText returns the level formatted by String
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText parses a level name, warning is accepted for warn
+func (l *Level) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+
+	name := s
+	if strings.EqualFold(name, "warning") {
+		name = "warn"
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("invalid log level %q, the levels are debug, info, warn and error", s)
+	}
+
+	*l = Level(level)
+
+	return nil
+}
+
+// valueTypes are the value types whose validation rules apply to validatedValue
+var valueTypes = []interface{}{Duration(0), URL{}, IP{}, CIDR{}, Regexp{}, Level(0)}
+
+// validatedValue returns the value the validation rules of a value type apply
+// to: a Duration is compared as a time.Duration and the other types by their text
+func validatedValue(field reflect.Value) interface{} {
+	switch value := field.Interface().(type) {
+	case Duration:
+		return time.Duration(value)
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return nil
+	}
+}
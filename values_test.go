@@ -0,0 +1,302 @@
+package goconf
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type valuesConfig struct {
+	MaxUpload ByteSize `env:"VALUES_MAX_UPLOAD" yaml:"max_upload" validate:"gte=1024"`
+	Retention Duration `env:"VALUES_RETENTION" yaml:"retention" validate:"gte=1h"`
+	API       URL      `env:"VALUES_API" yaml:"api" validate:"required"`
+	BindIP    IP       `env:"VALUES_BIND_IP" yaml:"bind_ip"`
+	Trusted   []CIDR   `env:"VALUES_TRUSTED" yaml:"trusted"`
+	Origins   Regexp   `env:"VALUES_ORIGINS" yaml:"origins"`
+	LogLevel  Level    `env:"VALUES_LOG_LEVEL" yaml:"log_level" validate:"oneof=info warn error"`
+}
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		text        string
+		expected    ByteSize
+		formatted   string
+		expectedErr string
+	}{
+		{text: "0", expected: 0, formatted: "0B"},
+		{text: "1500", expected: 1500, formatted: "1500B"},
+		{text: "512MiB", expected: 512 * MiB, formatted: "512MiB"},
+		{text: "512 mib", expected: 512 * MiB, formatted: "512MiB"},
+		{text: "1MB", expected: MB, formatted: "1MB"},
+		{text: "1.5GB", expected: 1500 * MB, formatted: "1500MB"},
+		{text: "1.5KiB", expected: 1536, formatted: "1536B"},
+		{text: "2048KiB", expected: 2 * MiB, formatted: "2MiB"},
+		{text: "16EiB", expectedErr: `invalid byte size "16EiB": out of range`},
+		{text: "1.5B", expectedErr: `invalid byte size "1.5B": not a whole number of bytes`},
+		{text: "10M", expectedErr: `invalid byte size "10M": unknown unit "M"`},
+		{text: "MiB", expectedErr: `invalid byte size "MiB"`},
+		{text: "", expectedErr: `invalid byte size ""`},
+		{text: "1..5MB", expectedErr: `invalid byte size "1..5MB"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			var b ByteSize
+			err := b.UnmarshalText([]byte(test.text))
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, b)
+			assert.Equal(t, test.formatted, b.String())
+
+			var roundTrip ByteSize
+			require.NoError(t, roundTrip.UnmarshalText([]byte(b.String())))
+			assert.Equal(t, b, roundTrip)
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		text        string
+		expected    time.Duration
+		formatted   string
+		expectedErr string
+	}{
+		{text: "0", expected: 0, formatted: "0s"},
+		{text: "90s", expected: 90 * time.Second, formatted: "1m30s"},
+		{text: "1h30m", expected: 90 * time.Minute, formatted: "1h30m"},
+		{text: "2h", expected: 2 * time.Hour, formatted: "2h"},
+		{text: "7d", expected: 7 * day, formatted: "7d"},
+		{text: "1.5d", expected: 36 * time.Hour, formatted: "1d12h"},
+		{text: "2w1d", expected: 15 * day, formatted: "15d"},
+		{text: "1d0h30m", expected: day + 30*time.Minute, formatted: "1d30m"},
+		{text: "-1d500ms", expected: -(day + 500*time.Millisecond), formatted: "-1d500ms"},
+		{text: "+5s", expected: 5 * time.Second, formatted: "5s"},
+		{text: "d", expectedErr: `invalid duration "d"`},
+		{text: "5", expectedErr: `invalid duration "5"`},
+		{text: "1y", expectedErr: `invalid duration "1y"`},
+		{text: "", expectedErr: `invalid duration ""`},
+		{text: "200000w", expectedErr: `invalid duration "200000w"`},
+		{text: "+-5s", expectedErr: `invalid duration "+-5s"`},
+		{text: "--5s", expectedErr: `invalid duration "--5s"`},
+		{text: "-", expectedErr: `invalid duration "-"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			var d Duration
+			err := d.UnmarshalText([]byte(test.text))
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, d.Duration())
+			assert.Equal(t, test.formatted, d.String())
+
+			var roundTrip Duration
+			require.NoError(t, roundTrip.UnmarshalText([]byte(d.String())))
+			assert.Equal(t, d, roundTrip)
+		})
+	}
+}
+
+func TestTextValues(t *testing.T) {
+	var u URL
+	require.NoError(t, u.UnmarshalText([]byte("postgres://db:5432/app?sslmode=disable")))
+	assert.Equal(t, "db:5432", u.Host)
+	assert.Equal(t, "postgres://db:5432/app?sslmode=disable", u.String())
+	assert.EqualError(t, u.UnmarshalText([]byte("://db")), `invalid URL "://db": parse "://db": missing protocol scheme`)
+	assert.EqualError(t, u.UnmarshalText([]byte("/api")), `invalid URL "/api": missing scheme`)
+	require.NoError(t, u.UnmarshalText(nil))
+	assert.Equal(t, URL{}, u)
+	assert.Empty(t, u.String())
+
+	var ip IP
+	require.NoError(t, ip.UnmarshalText([]byte("::1")))
+	assert.True(t, ip.IsLoopback())
+	assert.Equal(t, "::1", ip.String())
+	assert.EqualError(t, ip.UnmarshalText([]byte("10.0.0.256")), `invalid IP address "10.0.0.256"`)
+	require.NoError(t, ip.UnmarshalText(nil))
+	assert.Empty(t, ip.String())
+
+	var c CIDR
+	require.NoError(t, c.UnmarshalText([]byte("10.0.0.0/8")))
+	assert.Equal(t, "10.0.0.0/8", c.String())
+	assert.EqualError(t, c.UnmarshalText([]byte("10.0.0.0")), `invalid CIDR "10.0.0.0"`)
+	require.NoError(t, c.UnmarshalText(nil))
+	assert.Empty(t, c.String())
+
+	var r Regexp
+	require.NoError(t, r.UnmarshalText([]byte(`^api-\d+$`)))
+	assert.True(t, r.MatchString("api-1"))
+	assert.Equal(t, `^api-\d+$`, r.String())
+	assert.EqualError(t, r.UnmarshalText([]byte("(")), "invalid regular expression \"(\": error parsing regexp: missing closing ): `(`")
+	require.NoError(t, r.UnmarshalText(nil))
+	assert.Nil(t, r.Regexp)
+	assert.Empty(t, r.String())
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		text        string
+		expected    Level
+		formatted   string
+		expectedErr string
+	}{
+		{text: "debug", expected: LevelDebug, formatted: "debug"},
+		{text: "INFO", expected: LevelInfo, formatted: "info"},
+		{text: "Warning", expected: LevelWarn, formatted: "warn"},
+		{text: "error", expected: LevelError, formatted: "error"},
+		{text: "info+2", expected: LevelInfo + 2, formatted: "info+2"},
+		{text: "verbose", expectedErr: `invalid log level "verbose", the levels are debug, info, warn and error`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			var l Level
+			err := l.UnmarshalText([]byte(test.text))
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, l)
+			assert.Equal(t, test.formatted, l.String())
+			assert.Equal(t, test.expected.Slog(), l.Slog())
+		})
+	}
+}
+
+func TestValuesParse(t *testing.T) {
+	t.Setenv("VALUES_MAX_UPLOAD", "512MiB")
+	t.Setenv("VALUES_RETENTION", "1d12h")
+	t.Setenv("VALUES_API", "https://api.example.com/v1")
+	t.Setenv("VALUES_BIND_IP", "10.0.0.1")
+	t.Setenv("VALUES_TRUSTED", "10.0.0.0/8,fd00::/64")
+	t.Setenv("VALUES_ORIGINS", `^https://.*\.example\.com$`)
+	t.Setenv("VALUES_LOG_LEVEL", "warn")
+
+	var fromEnv valuesConfig
+	require.NoError(t, ParseEnv(&fromEnv))
+
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte(`max_upload: 512MiB
+retention: 1d12h
+api: https://api.example.com/v1
+bind_ip: 10.0.0.1
+trusted: [10.0.0.0/8, fd00::/64]
+origins: ^https://.*\.example\.com$
+log_level: warn
+`), 0o600))
+
+	var fromYAML valuesConfig
+	require.NoError(t, ParseYaml(&fromYAML, filePath))
+
+	assert.Equal(t, fromEnv, fromYAML)
+	assert.Equal(t, 512*MiB, fromEnv.MaxUpload)
+	assert.Equal(t, 36*time.Hour, fromEnv.Retention.Duration())
+	assert.Equal(t, "api.example.com", fromEnv.API.Host)
+	assert.Equal(t, "10.0.0.1", fromEnv.BindIP.String())
+	require.Len(t, fromEnv.Trusted, 2)
+	assert.Equal(t, "fd00::/64", fromEnv.Trusted[1].String())
+	assert.True(t, fromEnv.Origins.MatchString("https://www.example.com"))
+	assert.Equal(t, LevelWarn, fromEnv.LogLevel)
+
+	data, err := yaml.Marshal(fromYAML)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filePath, data, 0o600))
+
+	var roundTrip valuesConfig
+	require.NoError(t, ParseYaml(&roundTrip, filePath))
+	assert.Equal(t, fromYAML, roundTrip)
+
+	t.Setenv("VALUES_MAX_UPLOAD", "10M")
+	assert.ErrorContains(t, ParseEnv(&fromEnv), `invalid byte size "10M": unknown unit "M"`)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("retention: 1 day\n"), 0o600))
+	assert.ErrorContains(t, ParseYaml(&fromYAML, filePath), `invalid duration "1 day"`)
+}
+
+func TestValuesPrint(t *testing.T) {
+	var cfg valuesConfig
+	require.NoError(t, cfg.API.UnmarshalText([]byte("https://api.example.com/v1")))
+	require.NoError(t, cfg.Origins.UnmarshalText([]byte("^a+$")))
+	cfg.MaxUpload = 512 * MiB
+	cfg.Retention = Duration(7 * day)
+	cfg.LogLevel = LevelWarn
+
+	fields := buildFields(KeyNamingField, structValue(cfg))
+	assert.Equal(t, [][]string{
+		{"MaxUpload", "512MiB"},
+		{"Retention", "7d"},
+		{"API", "https://api.example.com/v1"},
+		{"BindIP", ""},
+		{"Trusted", "[]"},
+		{"Origins", "^a+$"},
+		{"LogLevel", "warn"},
+	}, tableRows(fields))
+
+	data, err := json.Marshal(jsonMap(KeyNamingField, fields))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"MaxUpload": "512MiB",
+		"Retention": "7d",
+		"API": "https://api.example.com/v1",
+		"BindIP": "",
+		"Trusted": null,
+		"Origins": "^a+$",
+		"LogLevel": "warn"
+	}`, string(data))
+
+	var b bytes.Buffer
+	require.NoError(t, renderYAML(&b, fields))
+	assert.Contains(t, b.String(), "max_upload: 512MiB\nretention: 7d\napi: https://api.example.com/v1\n")
+}
+
+func TestValuesValidation(t *testing.T) {
+	valid := func() valuesConfig {
+		var cfg valuesConfig
+		require.NoError(t, cfg.API.UnmarshalText([]byte("https://api.example.com")))
+		cfg.MaxUpload = KiB
+		cfg.Retention = Duration(time.Hour)
+		cfg.LogLevel = LevelInfo
+
+		return cfg
+	}
+
+	assert.NoError(t, NewValidator().Struct(valid()))
+
+	tests := []struct {
+		name   string
+		modify func(cfg *valuesConfig)
+		field  string
+	}{
+		{name: "byte size bound", modify: func(cfg *valuesConfig) { cfg.MaxUpload = 1023 }, field: "MaxUpload"},
+		{name: "duration bound", modify: func(cfg *valuesConfig) { cfg.Retention = Duration(time.Minute) }, field: "Retention"},
+		{name: "required URL", modify: func(cfg *valuesConfig) { cfg.API = URL{} }, field: "API"},
+		{name: "level oneof", modify: func(cfg *valuesConfig) { cfg.LogLevel = LevelDebug }, field: "LogLevel"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := valid()
+			test.modify(&cfg)
+
+			assert.ErrorContains(t, NewValidator().Struct(cfg), "'"+test.field+"'")
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package goconf
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	// warnTag holds validation rules that only produce a warning when they fail
+	warnTag = "warn"
+	// deprecatedTag marks a field as deprecated, its value is the deprecation notice
+	deprecatedTag = "deprecated"
+	// replacedByTag names the sibling field that receives the value of a deprecated field
+	replacedByTag = "replacedBy"
+)
+
+// WarningHandler receives the warnings collected by Load
+type WarningHandler func(warning string)
+
+var currentWarningHandler WarningHandler = func(warning string) {
+	log.Printf("goconf: warning: %s", warning)
+}
+
+// SetWarningHandler sets the function receiving the warnings collected by Load.
+// By default warnings are written to the standard logger.
+func SetWarningHandler(handler WarningHandler) {
+	currentWarningHandler = handler
+}
+
+// collectWarnings returns the warnings of a Warner and of the tags of the Printer
+// output, checking the `warn` tags with v
+func collectWarnings(c Configer, v *Validator) []string {
+	var warnings []string
+
+	if w, ok := c.(Warner); ok {
+		warnings = append(warnings, w.Warn()...)
+	}
+
+	if p, ok := c.(Printer); ok {
+		warnings = append(warnings, fieldWarnings(v, printerValue(p).Type(), printerFields(currentKeyNaming, p))...)
+	}
+
+	return warnings
+}
+
+// Warnings returns the non-fatal findings of the `warn` and `deprecated` tags of a
+// configuration struct. Load reports them automatically for Printer implementations.
+//
+// The `warn` tag holds validation rules, checked with DefaultValidator, that produce
+// a warning instead of an error, and the `deprecated` tag produces a warning when the field is set through its
+// environment variable or its key in the YAML file last read by ParseYaml. Fields
+// of types not read by ParseYaml are set if they hold a non-zero value:
+//
+//	type Config struct {
+//	    PoolSize int    `env:"POOL_SIZE" validate:"gte=1" warn:"lte=100"`
+//	    Timeout  int    `env:"TIMEOUT_SECONDS"`
+//	    OldTTL   int    `env:"TTL" deprecated:"use TIMEOUT_SECONDS instead" replacedBy:"Timeout"`
+//	}
+func Warnings(config interface{}) []string {
+	values := structValue(config)
+
+	return fieldWarnings(defaultValidator, values.Type(), buildFields(currentKeyNaming, values))
+}
+
+// fieldWarnings returns the warnings of the `warn` and `deprecated` tags of the
+// fields of a configuration of type t, checking the `warn` tags with v
+func fieldWarnings(v *Validator, t reflect.Type, fields []Field) []string {
+	var warnings []string
+
+	yamlKeys, fromYAML := deprecatedYAMLKeys(t)
+
+	for _, f := range FlattenFields(fields) {
+		if rules, ok := f.Tags.Lookup(warnTag); ok {
+			if err := v.Var(f.raw, rules); err != nil {
+				warnings = append(warnings, warnTagMessage(f, rules))
+			}
+		}
+
+		if notice, ok := f.Tags.Lookup(deprecatedTag); ok {
+			if name, set := deprecatedFieldSet(f, yamlKeys, fromYAML); set {
+				warnings = append(warnings, fmt.Sprintf("%s is deprecated: %s", name, notice))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// warnTagMessage returns the warning of a failed `warn` tag, secret values are masked
+func warnTagMessage(f Field, rules string) string {
+	return fmt.Sprintf("%s=%s does not satisfy %s", f.Key, f.ValueString(), rules)
+}
+
+// deprecatedFieldSet reports whether a deprecated field is set and returns the
+// name to report, the environment variable if it is set and the field key otherwise.
+// If the configuration was read by ParseYaml, the field is set if its key is in
+// yamlKeys, so a key explicitly set to a zero value is reported as well.
+func deprecatedFieldSet(f Field, yamlKeys map[string]bool, fromYAML bool) (string, bool) {
+	if f.Source != "" {
+		if _, ok := os.LookupEnv(f.Source); ok {
+			return f.Source, true
+		}
+	}
+
+	if fromYAML {
+		return f.Key, yamlKeys[strings.Join(f.Path, ".")]
+	}
+
+	return f.Key, f.raw != nil && !reflect.ValueOf(f.raw).IsZero()
+}
+
+var (
+	yamlKeysMu sync.RWMutex
+	// yamlKeys holds the paths of the deprecated fields whose key was present in
+	// the YAML file last read by ParseYaml, per configuration type
+	yamlKeys = make(map[reflect.Type]map[string]bool)
+)
+
+// recordDeprecatedYAMLKeys records the deprecated fields of config set by source
+func recordDeprecatedYAMLKeys(config interface{}, source sourcePresence) {
+	values := structValue(config)
+	if values.Kind() != reflect.Struct {
+		return
+	}
+
+	keys := make(map[string]bool)
+	collectDeprecatedKeys(values.Type(), source, "", keys)
+
+	yamlKeysMu.Lock()
+	defer yamlKeysMu.Unlock()
+
+	yamlKeys[values.Type()] = keys
+}
+
+func collectDeprecatedKeys(t reflect.Type, source sourcePresence, path string, keys map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		fieldPath := joinGoPath(path, structField.Name)
+
+		if _, ok := structField.Tag.Lookup(deprecatedTag); ok && source.has(structField) {
+			keys[fieldPath] = true
+		}
+
+		if structField.Type.Kind() == reflect.Struct && !isEnvLeaf(structField.Type) && !isOptionalType(structField.Type) {
+			collectDeprecatedKeys(structField.Type, source.nested(structField), fieldPath, keys)
+		}
+	}
+}
+
+// deprecatedYAMLKeys returns the deprecated fields set in the YAML file last read
+// into a configuration of type t, and whether such a file was read
+func deprecatedYAMLKeys(t reflect.Type) (map[string]bool, bool) {
+	yamlKeysMu.RLock()
+	defer yamlKeysMu.RUnlock()
+
+	keys, ok := yamlKeys[t]
+
+	return keys, ok
+}
+
+// applyReplacements copies the values of deprecated fields set by source into the
+// sibling field named by their `replacedBy` tag, unless source also sets that field.
+// Whether a field is set does not depend on its value, so defaults applied by the
+// source, such as envDefault, do not prevent the copy.
+func applyReplacements(config interface{}, source sourcePresence) error {
+	values := reflect.ValueOf(config)
+	if values.Kind() != reflect.Ptr || values.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	return applyNestedReplacements(values.Elem(), source)
+}
+
+func applyNestedReplacements(values reflect.Value, source sourcePresence) error {
+	for i := 0; i < values.NumField(); i++ {
+		field := values.Field(i)
+		structField := values.Type().Field(i)
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		if field.Kind() == reflect.Struct {
+			if err := applyNestedReplacements(field, source.nested(structField)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		target, ok := structField.Tag.Lookup(replacedByTag)
+		if !ok {
+			continue
+		}
+
+		replacement := values.FieldByName(target)
+		if !replacement.IsValid() || !replacement.CanSet() {
+			return fmt.Errorf("field %s is replaced by unknown field %s", structField.Name, target)
+		}
+
+		if !field.Type().AssignableTo(replacement.Type()) {
+			return fmt.Errorf("field %s of type %s cannot replace field %s of type %s",
+				structField.Name, field.Type(), target, replacement.Type())
+		}
+
+		replacementField, _ := values.Type().FieldByName(target)
+		if source.has(structField) && !source.has(replacementField) {
+			replacement.Set(field)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+package goconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wgarunap/goconf/mocks"
+)
+
+type warningsConfig struct {
+	PoolSize int    `env:"GOCONF_TEST_POOL_SIZE" yaml:"pool_size" warn:"lte=100"`
+	Token    string `env:"GOCONF_TEST_TOKEN" secret:"true" warn:"min=32"`
+	Timeout  int    `env:"GOCONF_TEST_TIMEOUT" yaml:"timeout"`
+	OldTTL   int    `env:"GOCONF_TEST_TTL" yaml:"ttl" deprecated:"use GOCONF_TEST_TIMEOUT instead" replacedBy:"Timeout"`
+	Legacy   string `yaml:"legacy" deprecated:"remove it"`
+}
+
+func TestWarnings(t *testing.T) {
+	tests := map[string]struct {
+		config   warningsConfig
+		env      map[string]string
+		expected []string
+	}{
+		"no warnings": {
+			config: warningsConfig{PoolSize: 10, Token: "0123456789abcdef0123456789abcdef"},
+		},
+		"warn tags": {
+			config: warningsConfig{PoolSize: 500, Token: "short-token"},
+			expected: []string{
+				"PoolSize=500 does not satisfy lte=100",
+				"Token=*************** does not satisfy min=32",
+			},
+		},
+		"deprecated env var is set": {
+			config:   warningsConfig{Token: "0123456789abcdef0123456789abcdef"},
+			env:      map[string]string{"GOCONF_TEST_TTL": "0"},
+			expected: []string{"GOCONF_TEST_TTL is deprecated: use GOCONF_TEST_TIMEOUT instead"},
+		},
+		"deprecated field has a value": {
+			config:   warningsConfig{Token: "0123456789abcdef0123456789abcdef", Legacy: "yes"},
+			expected: []string{"Legacy is deprecated: remove it"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			assert.Equal(t, test.expected, Warnings(test.config))
+		})
+	}
+}
+
+func TestLoadReportsWarnings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	originalHandler := currentWarningHandler
+	defer func() { currentWarningHandler = originalHandler }()
+
+	var warnings []string
+	SetWarningHandler(func(warning string) {
+		warnings = append(warnings, warning)
+	})
+
+	mockConfiger := mocks.NewMockConfiger(ctrl)
+	mockWarner := mocks.NewMockWarner(ctrl)
+	mockPrinter := mocks.NewMockPrinter(ctrl)
+
+	mockConfiger.EXPECT().Register().Return(nil)
+	mockWarner.EXPECT().Warn().Return([]string{"TLS disabled in production"})
+	mockPrinter.EXPECT().Print().Return(warningsConfig{PoolSize: 500, Token: "0123456789abcdef0123456789abcdef"}).AnyTimes()
+
+	config := &struct {
+		*mocks.MockConfiger
+		*mocks.MockWarner
+		*mocks.MockPrinter
+	}{
+		MockConfiger: mockConfiger,
+		MockWarner:   mockWarner,
+		MockPrinter:  mockPrinter,
+	}
+
+	captureStdout(t, func() error {
+		return Load(config)
+	})
+
+	assert.Equal(t, []string{"TLS disabled in production", "PoolSize=500 does not satisfy lte=100"}, warnings)
+}
+
+type replacementDefaultsConfig struct {
+	Timeout    int `env:"GOCONF_TEST_TIMEOUT" yaml:"timeout" envDefault:"30" default:"30"`
+	OldTTL     int `env:"GOCONF_TEST_TTL" yaml:"ttl" deprecated:"use GOCONF_TEST_TIMEOUT instead" replacedBy:"Timeout"`
+	Retries    int `env:"GOCONF_TEST_RETRIES" yaml:"retries" default:"3"`
+	RetryCount int `env:"GOCONF_TEST_RETRY_COUNT" yaml:"retry_count" deprecated:"use GOCONF_TEST_RETRIES instead" replacedBy:"Retries"`
+}
+
+func TestDeprecatedReplacement(t *testing.T) {
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("GOCONF_TEST_TTL", "30")
+
+		var cfg warningsConfig
+		require.NoError(t, ParseEnv(&cfg))
+		assert.Equal(t, 30, cfg.Timeout)
+	})
+
+	t.Run("env replacement already set", func(t *testing.T) {
+		t.Setenv("GOCONF_TEST_TTL", "30")
+		t.Setenv("GOCONF_TEST_TIMEOUT", "60")
+
+		var cfg warningsConfig
+		require.NoError(t, ParseEnv(&cfg))
+		assert.Equal(t, 60, cfg.Timeout)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		yamlFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(yamlFile, []byte("ttl: 45\n"), 0o644))
+
+		var cfg warningsConfig
+		require.NoError(t, ParseYaml(&cfg, yamlFile))
+		t.Cleanup(func() { forgetYAMLKeys(cfg) })
+
+		assert.Equal(t, 45, cfg.Timeout)
+		assert.Contains(t, warningsWithNaming(KeyNamingYAML, cfg), "ttl is deprecated: use GOCONF_TEST_TIMEOUT instead")
+	})
+
+	t.Run("env replacement with envDefault", func(t *testing.T) {
+		t.Setenv("GOCONF_TEST_TTL", "5")
+		t.Setenv("GOCONF_TEST_RETRY_COUNT", "5")
+
+		var cfg replacementDefaultsConfig
+		require.NoError(t, ApplyDefaults(&cfg))
+		require.NoError(t, ParseEnv(&cfg))
+		assert.Equal(t, 5, cfg.Timeout)
+		assert.Equal(t, 5, cfg.Retries)
+	})
+
+	t.Run("env replacement with default", func(t *testing.T) {
+		t.Setenv("GOCONF_TEST_TTL", "0")
+		t.Setenv("GOCONF_TEST_RETRY_COUNT", "0")
+
+		var cfg replacementDefaultsConfig
+		require.NoError(t, ApplyDefaults(&cfg))
+		require.NoError(t, ParseEnv(&cfg))
+		assert.Equal(t, 0, cfg.Timeout)
+		assert.Equal(t, 0, cfg.Retries)
+	})
+
+	t.Run("env replacement without variables", func(t *testing.T) {
+		var cfg replacementDefaultsConfig
+		require.NoError(t, ApplyDefaults(&cfg))
+		require.NoError(t, ParseEnv(&cfg))
+		assert.Equal(t, 30, cfg.Timeout)
+		assert.Equal(t, 3, cfg.Retries)
+	})
+
+	t.Run("yaml key set to zero", func(t *testing.T) {
+		yamlFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(yamlFile, []byte("ttl: 0\nlegacy: \"\"\ntoken: 0123456789abcdef0123456789abcdef\n"), 0o644))
+
+		var cfg warningsConfig
+		require.NoError(t, ParseYaml(&cfg, yamlFile))
+		t.Cleanup(func() { forgetYAMLKeys(cfg) })
+
+		assert.Equal(t, []string{
+			"ttl is deprecated: use GOCONF_TEST_TIMEOUT instead",
+			"legacy is deprecated: remove it",
+		}, warningsWithNaming(KeyNamingYAML, cfg))
+
+		var withDefault replacementDefaultsConfig
+		require.NoError(t, ApplyDefaults(&withDefault))
+		require.NoError(t, ParseYaml(&withDefault, yamlFile))
+		t.Cleanup(func() { forgetYAMLKeys(withDefault) })
+
+		assert.Equal(t, 0, withDefault.Timeout)
+		assert.Equal(t, 3, withDefault.Retries)
+	})
+
+	t.Run("yaml key absent", func(t *testing.T) {
+		yamlFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(yamlFile, []byte("timeout: 10\ntoken: 0123456789abcdef0123456789abcdef\n"), 0o644))
+
+		cfg := warningsConfig{Legacy: "set by a Defaulter"}
+		require.NoError(t, ParseYaml(&cfg, yamlFile))
+		t.Cleanup(func() { forgetYAMLKeys(cfg) })
+
+		assert.Empty(t, warningsWithNaming(KeyNamingYAML, cfg))
+	})
+
+	t.Run("unknown replacement", func(t *testing.T) {
+		cfg := struct {
+			Old int `replacedBy:"Missing"`
+		}{Old: 1}
+
+		require.ErrorContains(t, applyReplacements(&cfg, yamlSource{}), "field Old is replaced by unknown field Missing")
+	})
+
+	t.Run("incompatible replacement", func(t *testing.T) {
+		cfg := struct {
+			Old int `replacedBy:"New"`
+			New string
+		}{Old: 1}
+
+		require.ErrorContains(t, applyReplacements(&cfg, yamlSource{}), "field Old of type int cannot replace field New of type string")
+	})
+}
+
+// forgetYAMLKeys removes the deprecated keys recorded by ParseYaml for the type of config
+func forgetYAMLKeys(config interface{}) {
+	yamlKeysMu.Lock()
+	defer yamlKeysMu.Unlock()
+
+	delete(yamlKeys, structValue(config).Type())
+}
+
+// warningsWithNaming returns the tag warnings of config using the given key naming mode
+func warningsWithNaming(naming KeyNaming, config interface{}) []string {
+	original := currentKeyNaming
+	defer func() { currentKeyNaming = original }()
+
+	SetKeyNaming(naming)
+
+	return Warnings(config)
+}
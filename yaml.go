@@ -1,8 +1,12 @@
 package goconf
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -18,6 +22,15 @@ import (
 // Returns:
 //   - error: Returns error if file reading or YAML parsing fails.
 //
+// Values of fields tagged with `deprecated` and `replacedBy` are copied into the
+// replacement field if their key is present in the file and the one of the
+// replacement is not.
+//
+// Fields of keys absent from the file keep their value, so the defaults set by Load
+// or ApplyDefaults before ParseYaml are kept and a key explicitly set to a zero value
+// overrides them. Maps present in the file replace the map of the field rather than
+// being merged into it.
+//
 // Example:
 //
 //	type Config struct {
@@ -35,9 +48,223 @@ func ParseYaml(config interface{}, filePath string) error {
 		return fmt.Errorf("failed to read YAML file %s: %w", filePath, err)
 	}
 
-	if err := yaml.Unmarshal(data, config); err != nil {
+	var source sourcePresence
+	if currentYAMLKeyMatching == YAMLKeyMatchingLoose {
+		source, err = unmarshalLooseYAML(data, config)
+	} else {
+		source, err = unmarshalYAML(data, config)
+	}
+
+	if err != nil {
 		return fmt.Errorf("failed to unmarshal YAML data: %w", err)
 	}
 
-	return nil
+	recordDeprecatedYAMLKeys(config, source)
+
+	return applyReplacements(config, source)
+}
+
+// unmarshalYAML decodes data into config and returns the presence of its keys
+func unmarshalYAML(data []byte, config interface{}) (sourcePresence, error) {
+	source, err := newYAMLSource(data)
+	if err != nil {
+		return nil, err
+	}
+
+	resetPresentMaps(reflect.ValueOf(config), source)
+
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// resetPresentMaps sets the map fields whose key is present in source to nil, since
+// yaml.v3 merges the keys of a mapping into a non-nil map, e.g. one set by a default
+func resetPresentMaps(values reflect.Value, source sourcePresence) {
+	values = reflect.Indirect(values)
+	if values.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < values.NumField(); i++ {
+		field := values.Field(i)
+		structField := values.Type().Field(i)
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		switch {
+		case field.Kind() == reflect.Map && source.has(structField):
+			field.Set(reflect.Zero(field.Type()))
+		case reflect.Indirect(field).Kind() == reflect.Struct:
+			resetPresentMaps(field, source.nested(structField))
+		}
+	}
+}
+
+// unmarshalLooseYAML decodes data into config after renaming the keys that loosely
+// match a field to its YAML key, and returns the presence of the renamed keys
+func unmarshalLooseYAML(data []byte, config interface{}) (sourcePresence, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return yamlSource{}, nil
+	}
+
+	var errs []error
+	for _, err := range matchYAMLKeys(doc.Content[0], reflect.TypeOf(config)) {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	source := yamlSource{node: doc.Content[0]}
+	resetPresentMaps(reflect.ValueOf(config), source)
+
+	if err := doc.Content[0].Decode(config); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// YAMLKeyMatching defines how ParseYaml and CheckFile match YAML keys to struct fields
+type YAMLKeyMatching string
+
+const (
+	// YAMLKeyMatchingExact matches keys equal to the yaml tag name, or to the
+	// lowercased field name without a tag (default)
+	YAMLKeyMatchingExact YAMLKeyMatching = "exact"
+	// YAMLKeyMatchingLoose also matches keys that differ in case, hyphens and
+	// underscores, e.g. appName, app_name and app-name for the key app_name
+	YAMLKeyMatchingLoose YAMLKeyMatching = "loose"
+)
+
+var currentYAMLKeyMatching = YAMLKeyMatchingExact
+
+// SetYAMLKeyMatching sets how YAML keys are matched to struct fields. With
+// YAMLKeyMatchingLoose, a file holding two keys that match the same field, or a
+// key that matches more than one field, fails to load.
+func SetYAMLKeyMatching(matching YAMLKeyMatching) {
+	currentYAMLKeyMatching = matching
+}
+
+// CurrentYAMLKeyMatching returns the YAMLKeyMatching set with SetYAMLKeyMatching
+func CurrentYAMLKeyMatching() YAMLKeyMatching {
+	return currentYAMLKeyMatching
+}
+
+// yamlKeyError is a key matching more than one field, or a field matched by more than one key
+type yamlKeyError struct {
+	key     *yaml.Node
+	message string
+}
+
+func (e yamlKeyError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.key.Line, e.message)
+}
+
+// matchYAMLKeys renames the mapping keys that loosely match a field of the type t
+// to its YAML key, including the keys of nested structs. Ambiguous keys are kept
+// and returned.
+func matchYAMLKeys(node *yaml.Node, t reflect.Type) []yamlKeyError {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case node == nil:
+		return nil
+	case node.Kind == yaml.AliasNode:
+		return matchYAMLKeys(node.Alias, t)
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		var errs []yamlKeyError
+		if node.Kind == yaml.SequenceNode {
+			for _, item := range node.Content {
+				errs = append(errs, matchYAMLKeys(item, t.Elem())...)
+			}
+		}
+
+		return errs
+	case t.Kind() == reflect.Map:
+		var errs []yamlKeyError
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				errs = append(errs, matchYAMLKeys(node.Content[i+1], t.Elem())...)
+			}
+		}
+
+		return errs
+	case t.Kind() != reflect.Struct || node.Kind != yaml.MappingNode || isYAMLLeaf(t):
+		return nil
+	}
+
+	fields := yamlFields(t)
+
+	loose := make(map[string][]string, len(fields))
+	for key := range fields {
+		loose[looseYAMLKey(key)] = append(loose[looseYAMLKey(key)], key)
+	}
+
+	var errs []yamlKeyError
+
+	// matched holds the key of the file that matched each field first
+	matched := make(map[string]string)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+		if keyNode.Tag == "!!merge" {
+			continue
+		}
+
+		key := keyNode.Value
+		if _, ok := fields[key]; !ok {
+			candidates := loose[looseYAMLKey(key)]
+
+			switch len(candidates) {
+			case 0:
+				continue
+			case 1:
+				key = candidates[0]
+			default:
+				sort.Strings(candidates)
+				errs = append(errs, yamlKeyError{
+					key:     keyNode,
+					message: fmt.Sprintf("key %q matches the keys %s", keyNode.Value, strings.Join(candidates, " and ")),
+				})
+
+				continue
+			}
+		}
+
+		// Repeated keys are reported by the decoder
+		if first, ok := matched[key]; ok && first != keyNode.Value {
+			errs = append(errs, yamlKeyError{
+				key:     keyNode,
+				message: fmt.Sprintf("keys %q and %q both match the field %s", first, keyNode.Value, fields[key].path),
+			})
+			continue
+		}
+
+		matched[key] = keyNode.Value
+		keyNode.Value = key
+
+		errs = append(errs, matchYAMLKeys(valueNode, fields[key].typ)...)
+	}
+
+	return errs
+}
+
+// looseYAMLKey returns a key lowercased without hyphens and underscores
+func looseYAMLKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(key))
 }
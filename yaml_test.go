@@ -136,3 +136,66 @@ port: [invalid
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to unmarshal YAML data")
 }
+
+func TestParseYamlLooseKeys(t *testing.T) {
+	defer SetYAMLKeyMatching(YAMLKeyMatchingExact)
+
+	type Config struct {
+		AppName  string `yaml:"app_name"`
+		MaxConns int    `yaml:"maxConns" default:"10"`
+		Region   string
+		Servers  []struct {
+			HostName string `yaml:"host_name"`
+		} `yaml:"servers"`
+		Common struct {
+			LogLevel string `yaml:"log-level"`
+		} `yaml:",inline"`
+	}
+
+	parse := func(content string) (Config, error) {
+		yamlFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(yamlFile, []byte(content), 0o600))
+
+		var cfg Config
+		require.NoError(t, ApplyDefaults(&cfg))
+		err := ParseYaml(&cfg, yamlFile)
+
+		return cfg, err
+	}
+
+	content := "AppName: app\nmax_conns: 0\nREGION: eu\nservers:\n  - host-name: &host a\n  - HostName: *host\nlog_level: debug\n"
+
+	cfg, err := parse(content)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.AppName)
+	assert.Equal(t, 10, cfg.MaxConns)
+	require.Len(t, cfg.Servers, 2)
+	assert.Empty(t, cfg.Servers[0].HostName)
+
+	SetYAMLKeyMatching(YAMLKeyMatchingLoose)
+	assert.Equal(t, YAMLKeyMatchingLoose, CurrentYAMLKeyMatching())
+
+	cfg, err = parse(content)
+	require.NoError(t, err)
+	assert.Equal(t, "app", cfg.AppName)
+	assert.Equal(t, 0, cfg.MaxConns, "explicit zero keeps the default unset")
+	assert.Equal(t, "eu", cfg.Region)
+	require.Len(t, cfg.Servers, 2)
+	assert.Equal(t, "a", cfg.Servers[0].HostName)
+	assert.Equal(t, "a", cfg.Servers[1].HostName)
+	assert.Equal(t, "debug", cfg.Common.LogLevel)
+
+	_, err = parse("app_name: a\nappName: b\nservers:\n  - host_name: a\n    HOST-NAME: b\n")
+	assert.EqualError(t, err, "failed to unmarshal YAML data: line 2: keys \"app_name\" and \"appName\" both match the field AppName\n"+
+		"line 5: keys \"host_name\" and \"HOST-NAME\" both match the field HostName")
+
+	type ambiguous struct {
+		A string `yaml:"log_level"`
+		B string `yaml:"loglevel"`
+	}
+
+	yamlFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte("loglevel: a\nlog-level: b\n"), 0o600))
+	assert.EqualError(t, ParseYaml(new(ambiguous), yamlFile),
+		`failed to unmarshal YAML data: line 2: key "log-level" matches the keys log_level and loglevel`)
+}